@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const dependencyReportFile = "dependency_report.json"
+
+// dependencyReport 每个资源依赖的其他服务, 写入 ${outputDir}/dependency_report.json, 用于授予跨服务的权限
+type dependencyReport struct {
+	Version   string               `json:"version"`
+	Resources []resourceDependency `json:"resources"`
+	// key: 被依赖的服务, value: 依赖该服务的资源
+	Services map[string][]string `json:"services"`
+}
+
+type resourceDependency struct {
+	Name         string   `json:"name"`
+	File         string   `json:"file"`
+	Service      string   `json:"service"`      // 资源所属的服务, 即第一个tag
+	Dependencies []string `json:"dependencies"` // 资源调用的其他服务的API
+}
+
+// buildDependencyReport 根据每个资源的tags和API的tag统计资源依赖的其他服务
+func buildDependencyReport(report *coverageReport) *dependencyReport {
+	deps := dependencyReport{
+		Version:   report.Version,
+		Resources: []resourceDependency{},
+		Services:  make(map[string][]string),
+	}
+
+	for _, rs := range report.Resources {
+		item := resourceDependency{
+			Name:         rs.Name,
+			File:         rs.File,
+			Dependencies: []string{},
+		}
+		if len(rs.Tags) > 0 {
+			item.Service = rs.Tags[0]
+		}
+
+		products := append([]string{}, rs.Tags...)
+		for _, op := range rs.Operations {
+			products = append(products, op.Tag)
+		}
+		for _, product := range removeDuplicateValues(products) {
+			if product == item.Service || isEmptyTag(product) {
+				continue
+			}
+			item.Dependencies = append(item.Dependencies, product)
+			deps.Services[product] = append(deps.Services[product], rs.Name)
+		}
+		sort.Strings(item.Dependencies)
+		deps.Resources = append(deps.Resources, item)
+	}
+
+	for _, names := range deps.Services {
+		sort.Strings(names)
+	}
+	return &deps
+}
+
+func writeDependencyReport(dir string, report *dependencyReport) error {
+	content, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, dependencyReportFile), content, 0644)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"go/ast"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// resourceDescription 返回资源的描述, 优先使用 schema.Resource 的 Description, 其次使用provider文档中标题后的第一段
+func resourceDescription(rsName string, file *ast.File) string {
+	if description := schemaResourceDescription(file); description != "" {
+		return description
+	}
+	return docsDescription(rsName)
+}
+
+// schemaResourceDescription 查找资源文件中 &schema.Resource{Description: "..."} 的描述
+func schemaResourceDescription(file *ast.File) string {
+	var description string
+	ast.Inspect(file, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok || description != "" {
+			return description == ""
+		}
+		if sel, ok := lit.Type.(*ast.SelectorExpr); !ok || sel.Sel.Name != "Resource" {
+			return true
+		}
+
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			if key, ok := kv.Key.(*ast.Ident); !ok || key.Name != "Description" {
+				continue
+			}
+			if basic, ok := kv.Value.(*ast.BasicLit); ok {
+				if value, err := strconv.Unquote(basic.Value); err == nil {
+					description = strings.TrimSpace(value)
+				}
+			}
+		}
+		// 只使用最外层的 schema.Resource, 忽略 Elem 中嵌套的定义
+		return false
+	})
+	return description
+}
+
+// docsDescription 读取 docs/resources/xxx.md 或者 docs/data-sources/xxx.md 中标题后的第一段
+func docsDescription(rsName string) string {
+	var docPath string
+	if name := strings.TrimPrefix(rsName, "resource_huaweicloud_"); name != rsName {
+		docPath = basePath + "docs/resources/" + name + ".md"
+	} else if name := strings.TrimPrefix(rsName, "data_source_huaweicloud_"); name != rsName {
+		docPath = basePath + "docs/data-sources/" + name + ".md"
+	} else {
+		return ""
+	}
+
+	content, err := os.ReadFile(docPath)
+	if err != nil {
+		logDebug("the document of the resource is not found", "file", docPath, "reason", err)
+		return ""
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	paragraph := []string{}
+	inTitle := false
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if !inTitle {
+			inTitle = strings.HasPrefix(line, "# ")
+			continue
+		}
+		if line == "" {
+			if len(paragraph) > 0 {
+				break
+			}
+			continue
+		}
+		// 忽略标题后的提示和代码块, eg: -> **NOTE:** ...
+		if len(paragraph) == 0 && (strings.HasPrefix(line, "#") || strings.HasPrefix(line, "```") ||
+			strings.HasPrefix(line, "->") || strings.HasPrefix(line, "~>") || strings.HasPrefix(line, "!>")) {
+			break
+		}
+		paragraph = append(paragraph, line)
+	}
+	return strings.Join(paragraph, " ")
+}
+
+// docSummary 返回SDK函数注释的第一句, funcName 不为空时去掉注释开头的函数名称
+// eg: "Get retrieves a particular server based on its unique ID." 或者 "ShowVpc 查询VPC" -> "查询VPC"
+func docSummary(doc *ast.CommentGroup, funcName string) string {
+	if doc == nil {
+		return ""
+	}
+
+	paragraph := strings.Split(strings.TrimSpace(doc.Text()), "\n\n")[0]
+	summary := strings.Join(strings.Fields(paragraph), " ")
+	if index := strings.Index(summary, ". "); index > 0 {
+		summary = summary[:index+1]
+	}
+	if funcName != "" {
+		summary = strings.TrimSpace(strings.TrimPrefix(summary, funcName+" "))
+	}
+	return summary
+}
+
+// yamlString 将字符串转换为YAML中的双引号字符串, 避免其中的 : # 等字符破坏YAML的结构
+func yamlString(s string) string {
+	return strconv.Quote(s)
+}
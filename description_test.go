@@ -0,0 +1,56 @@
+package main
+
+import (
+	"go/ast"
+	"testing"
+)
+
+func TestDocsDescription(t *testing.T) {
+	defer setupFixtureScan(t)()
+
+	cases := map[string]string{
+		// 标题后第一段的多行合并为一行
+		"resource_huaweicloud_vpc": "Manages a VPC resource within HuaweiCloud. The CIDR block of the VPC: can not be changed after creation.",
+		// 标题后是提示而不是描述
+		"data_source_huaweicloud_vpcs": "",
+		// 没有文档
+		"resource_huaweicloud_vpc_subnet": "",
+	}
+	for rsName, want := range cases {
+		if got := docsDescription(rsName); got != want {
+			t.Errorf("docsDescription(%s) = %q, want %q", rsName, got, want)
+		}
+	}
+}
+
+func TestDocSummary(t *testing.T) {
+	comment := func(lines ...string) *ast.CommentGroup {
+		group := &ast.CommentGroup{}
+		for _, line := range lines {
+			group.List = append(group.List, &ast.Comment{Text: "// " + line})
+		}
+		return group
+	}
+
+	cases := []struct {
+		doc      *ast.CommentGroup
+		funcName string
+		want     string
+	}{
+		{comment("Create requests a server to be provisioned. The opts are required."), "",
+			"Create requests a server to be provisioned."},
+		{comment("ListOpts allows the filtering and sorting of paginated collections through", "the API."), "",
+			"ListOpts allows the filtering and sorting of paginated collections through the API."},
+		{comment("ShowVpc 查询VPC", "", "查询VPC详情。"), "ShowVpc", "查询VPC"},
+		{nil, "", ""},
+	}
+	for _, c := range cases {
+		if got := docSummary(c.doc, c.funcName); got != c.want {
+			t.Errorf("docSummary() = %q, want %q", got, c.want)
+		}
+	}
+
+	if got := yamlString(`a: "b" # c`); got != `"a: \"b\" # c"` {
+		t.Errorf("unexpected YAML string: %s", got)
+	}
+}
@@ -13,7 +13,6 @@ import (
 	"regexp"
 	"sort"
 	"strings"
-	"testing"
 )
 
 var basePath string
@@ -170,22 +169,6 @@ func parseFileSrc(filePath string) (fset *token.FileSet, f *ast.File, resourceFi
 	return set, f, resourceFilebytes
 }
 
-func TestMerge(t *testing.T) {
-	subPackage := basePath + "flexibleengine/"
-	mergeFunctionFileToInvokeFile(subPackage)
-	//searchPackage2(subPackage)
-}
-
-func TestGenApi(t *testing.T) {
-	//先解析 config:
-	configFilePath = basePath + "flexibleengine/config/config.go"
-	parseConfigFile(configFilePath)
-	//获取所有文件
-
-	subPackage := basePath + "flexibleengine/"
-	searchPackage2(subPackage, nil)
-}
-
 func searchPackage2(subPackage string, publicFuncs []string) {
 	set := token.NewFileSet()
 	packs, err := parser.ParseDir(set, subPackage, nil, 0)
@@ -525,15 +508,6 @@ func parseUriFromSdk(sdkFilePath string, sdkFunctionName string) (r CloudUri) {
 	return r
 }
 
-func TestParseUriFromSdk(t *testing.T) {
-	path := "github.com/chnsz/golangsdk/openstack/networking/v2/ports"
-
-	uri := parseUriFromSdk(path, "Update")
-	fmt.Println("TestConfig:", uri)
-	fmt.Printf("TestConfig:%#v", urlSupportsInRequestFile)
-	fmt.Printf("TestConfig:%#v", urlSupportsInUriFile)
-}
-
 var clientDeclInConfig = make(map[string]string)
 
 func getCategoryFromConfig(clientName string) string {
@@ -544,13 +518,6 @@ func getCategoryFromConfig(clientName string) string {
 	return ""
 }
 
-func TestConfig(t *testing.T) {
-	configFilePath = basePath + "flexibleengine/config/config.go"
-	parseConfigFile(configFilePath)
-	uri := getCategoryFromConfig("DnsV2Client")
-	fmt.Println("TestConfig:", uri)
-}
-
 func parseConfigFile(filePath string) {
 	set := token.NewFileSet()
 	f, err := parser.ParseFile(set, filePath, nil, 0)
@@ -829,12 +796,6 @@ func buildNewEndPointFile(filePath string) {
 
 }
 
-func TestEndpoint(t *testing.T) {
-	filePath := "/home/hm/go/src/github.com/terraform-providers/terraform-provider-flexibleengine/flexibleengine/config/endpoints.go"
-	buildNewEndPointFile(filePath)
-
-}
-
 //保存 openstack/instances.{func} : uri
 var urlSupportsInUriFile = make(map[string]string)
 var urlSupportsInRequestFile = make(map[string]CloudUri)
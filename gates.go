@@ -0,0 +1,169 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// 质量门禁不通过时的退出码
+const gateFailedExitCode = 3
+
+var (
+	// 命令行参数
+	maxUnresolved    int
+	failOnEmptyPaths bool
+	failOnEmptyTags  bool
+	baselineReport   string
+)
+
+func init() {
+	flag.IntVar(&maxUnresolved, "maxUnresolved", -1,
+		"the maximum number of unresolved SDK calls, -1 means no limit")
+	flag.BoolVar(&failOnEmptyPaths, "failOnEmptyPaths", false, "fail if any resource has no API")
+	flag.BoolVar(&failOnEmptyTags, "failOnEmptyTags", false, "fail if any resource or API has an empty or unknown tag")
+	flag.StringVar(&baselineReport, "baselineReport", "",
+		"the coverage report of a previous scan, fail if any resource or API is missing compared with it")
+}
+
+type gateResult struct {
+	Name    string   `json:"name"`
+	Passed  bool     `json:"passed"`
+	Summary string   `json:"summary"`
+	Details []string `json:"details,omitempty"`
+}
+
+// checkQualityGates 检查所有开启的质量门禁, 并将结果保存在报告中
+// 基线报告无法读取时 baselineReport 门禁不通过, 报告仍然正常生成
+func checkQualityGates(report *coverageReport) bool {
+	passed := true
+	addResult := func(result gateResult) {
+		report.Gates = append(report.Gates, result)
+		passed = passed && result.Passed
+	}
+
+	if maxUnresolved >= 0 {
+		addResult(checkUnresolvedCalls(report, maxUnresolved))
+	}
+	if failOnEmptyPaths {
+		addResult(checkEmptyPaths(report))
+	}
+	if failOnEmptyTags {
+		addResult(checkEmptyTags(report))
+	}
+	if baselineReport != "" {
+		baseline, err := readCoverageReport(baselineReport)
+		if err != nil {
+			addResult(gateResult{
+				Name:    "baselineReport",
+				Summary: fmt.Sprintf("failed to read the baseline report %s: %s", baselineReport, err),
+			})
+		} else {
+			addResult(checkRegression(report, baseline))
+		}
+	}
+
+	return passed
+}
+
+func checkUnresolvedCalls(report *coverageReport, limit int) gateResult {
+	details := []string{}
+	for _, call := range report.UnresolvedCalls {
+		details = append(details, fmt.Sprintf("%s: %s in %s", call.Resource, call.SdkFunc, call.SdkPackage))
+	}
+
+	count := len(report.UnresolvedCalls)
+	return gateResult{
+		Name:    "maxUnresolved",
+		Passed:  count <= limit,
+		Summary: fmt.Sprintf("%d unresolved SDK calls, the limit is %d", count, limit),
+		Details: details,
+	}
+}
+
+func checkEmptyPaths(report *coverageReport) gateResult {
+	details := []string{}
+	for _, rs := range report.Resources {
+		if len(rs.Operations) == 0 {
+			details = append(details, rs.Name)
+		}
+	}
+
+	return gateResult{
+		Name:    "failOnEmptyPaths",
+		Passed:  len(details) == 0,
+		Summary: fmt.Sprintf("%d resources have no API", len(details)),
+		Details: details,
+	}
+}
+
+func checkEmptyTags(report *coverageReport) gateResult {
+	details := []string{}
+	for _, rs := range report.Resources {
+		if hasEmptyTag(rs) {
+			details = append(details, rs.Name)
+		}
+	}
+
+	return gateResult{
+		Name:    "failOnEmptyTags",
+		Passed:  len(details) == 0,
+		Summary: fmt.Sprintf("%d resources have empty or unknown tags", len(details)),
+		Details: details,
+	}
+}
+
+// checkRegression 与基线比较, 资源或者API缺失时视为回退
+func checkRegression(report, baseline *coverageReport) gateResult {
+	current := make(map[string]*resourceReport)
+	for _, rs := range report.Resources {
+		current[rs.Name] = rs
+	}
+
+	details := []string{}
+	for _, old := range baseline.Resources {
+		rs, ok := current[old.Name]
+		if !ok {
+			details = append(details, fmt.Sprintf("%s: the resource is missing", old.Name))
+			continue
+		}
+
+		// 共用路径的 action 请求是不同的API, 与 paths 的键一样使用 path#action 区分
+		operations := make(map[string]bool)
+		for _, op := range rs.Operations {
+			operations[strings.ToUpper(op.Method)+" "+pathKey(op.Path, op.Action)] = true
+		}
+		for _, op := range old.Operations {
+			key := strings.ToUpper(op.Method) + " " + pathKey(op.Path, op.Action)
+			if !operations[key] {
+				details = append(details, fmt.Sprintf("%s: %s is missing", old.Name, key))
+			}
+		}
+	}
+
+	return gateResult{
+		Name:    "baselineReport",
+		Passed:  len(details) == 0,
+		Summary: fmt.Sprintf("%d regressions compared with %s", len(details), baseline.Version),
+		Details: details,
+	}
+}
+
+// logGateResults 输出质量门禁的汇总信息
+func logGateResults(report *coverageReport) {
+	s := report.Summary
+	logInfo("scan summary", "resources", s.Resources, "operations", s.Operations, "unresolved_calls", s.UnresolvedCalls,
+		"empty_path_resources", s.EmptyPathResources, "empty_tag_resources", s.EmptyTagResources, "errors", s.Errors)
+
+	for _, result := range report.Gates {
+		if result.Passed {
+			logInfo("quality gate passed", "gate", result.Name, "reason", result.Summary)
+			continue
+		}
+
+		logError("quality gate failed", "gate", result.Name, "reason", result.Summary)
+		for _, detail := range result.Details {
+			logError("quality gate violation", "gate", result.Name, "reason", detail)
+		}
+	}
+}
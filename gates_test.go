@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckUnresolvedCalls(t *testing.T) {
+	report := &coverageReport{
+		UnresolvedCalls: []unresolvedCall{
+			{Resource: "resource_huaweicloud_vpc", SdkFunc: "Get", SdkPackage: "networking/v1/vpcs"},
+		},
+	}
+
+	if result := checkUnresolvedCalls(report, 1); !result.Passed {
+		t.Errorf("expect passed with the limit 1, got %s", result.Summary)
+	}
+	result := checkUnresolvedCalls(report, 0)
+	want := "[resource_huaweicloud_vpc: Get in networking/v1/vpcs]"
+	if result.Passed || fmt.Sprint(result.Details) != want {
+		t.Errorf("unexpected result of the limit 0: %+v", result)
+	}
+}
+
+func TestCheckEmptyPathsAndTags(t *testing.T) {
+	report := &coverageReport{
+		Resources: []*resourceReport{
+			{Name: "resource_huaweicloud_vpc", Tags: []string{"VPC"},
+				Operations: []operationReport{{Method: "get", Path: "/v1/{project_id}/vpcs/{id}", Tag: "VPC"}}},
+			// 没有API的资源也没有tag
+			{Name: "resource_huaweicloud_empty"},
+			// API的tag未知
+			{Name: "resource_huaweicloud_unknown", Tags: []string{"VPC"},
+				Operations: []operationReport{{Method: "get", Path: "/v1/unknown", Tag: "unknown"}}},
+		},
+	}
+
+	if result := checkEmptyPaths(report); result.Passed || fmt.Sprint(result.Details) != "[resource_huaweicloud_empty]" {
+		t.Errorf("unexpected result of empty paths: %+v", result)
+	}
+	want := "[resource_huaweicloud_empty resource_huaweicloud_unknown]"
+	if result := checkEmptyTags(report); result.Passed || fmt.Sprint(result.Details) != want {
+		t.Errorf("unexpected result of empty tags: %+v", result)
+	}
+}
+
+func TestCheckQualityGatesInvalidBaseline(t *testing.T) {
+	oldMaxUnresolved, oldBaselineReport := maxUnresolved, baselineReport
+	defer func() {
+		maxUnresolved, baselineReport = oldMaxUnresolved, oldBaselineReport
+	}()
+
+	invalid := filepath.Join(t.TempDir(), "coverage_report.json")
+	if err := os.WriteFile(invalid, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// 基线报告无法读取或者格式错误时门禁不通过, 其他门禁照常检查
+	for _, path := range []string{invalid, filepath.Join(t.TempDir(), "missing.json")} {
+		maxUnresolved, baselineReport = 0, path
+		report := &coverageReport{}
+		if checkQualityGates(report) {
+			t.Errorf("expect the gates to fail with the baseline %s", path)
+		}
+		if len(report.Gates) != 2 || !report.Gates[0].Passed || report.Gates[1].Passed ||
+			!strings.Contains(report.Gates[1].Summary, "failed to read the baseline report") {
+			t.Errorf("unexpected gates with the baseline %s: %+v", path, report.Gates)
+		}
+	}
+}
+
+func TestCheckRegression(t *testing.T) {
+	stop := operationReport{Method: "post", Path: "/v1/{project_id}/cloudservers/action", Action: "os-stop"}
+	start := operationReport{Method: "post", Path: "/v1/{project_id}/cloudservers/action", Action: "os-start"}
+	baseline := &coverageReport{
+		Version: "v0.0.1",
+		Resources: []*resourceReport{
+			{Name: "resource_huaweicloud_compute_instance", Operations: []operationReport{stop, start}},
+			{Name: "resource_huaweicloud_vpc"},
+		},
+	}
+
+	// 共用路径的 action 请求缺少一个
+	report := &coverageReport{
+		Resources: []*resourceReport{
+			{Name: "resource_huaweicloud_compute_instance", Operations: []operationReport{stop}},
+		},
+	}
+	result := checkRegression(report, baseline)
+	want := []string{
+		"resource_huaweicloud_compute_instance: POST /v1/{project_id}/cloudservers/action#os-start is missing",
+		"resource_huaweicloud_vpc: the resource is missing",
+	}
+	if result.Passed || fmt.Sprint(result.Details) != fmt.Sprint(want) {
+		t.Errorf("unexpected regressions:\n got: %v\nwant: %v", result.Details, want)
+	}
+
+	report.Resources = baseline.Resources
+	if result := checkRegression(report, baseline); !result.Passed {
+		t.Errorf("expect no regression, got %v", result.Details)
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"go/parser"
 	"go/token"
 	"io/ioutil"
-	"log"
 	"regexp"
+	"strconv"
 	"strings"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 )
 
 var clientDeclInConfig = make(map[string]string)
@@ -21,17 +23,16 @@ func getCategoryFromConfig(clientName string) string {
 	return ""
 }
 
-func parseConfigFile(filePath string) {
+func parseConfigFile(filePath string) error {
 	set := token.NewFileSet()
 	f, err := parser.ParseFile(set, filePath, nil, 0)
 	if err != nil {
-		log.Println("Failed to parse file:", filePath, err)
-		return
+		return err
 	}
 
 	resourceFilebytes, err := ioutil.ReadFile(filePath)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	for _, d := range f.Decls {
@@ -45,7 +46,7 @@ func parseConfigFile(filePath string) {
 			reg := regexp.MustCompile(`NewServiceClient\("(.*)"`)
 			submatch := reg.FindAllStringSubmatch(funcSrc, -1)
 			if len(submatch) < 1 {
-				log.Println("skip parse config method:", funcName)
+				logDebug("skip the config method without service client", "func", funcName)
 				continue
 			}
 
@@ -56,41 +57,30 @@ func parseConfigFile(filePath string) {
 		}
 	}
 
-	//log.Println("[DEBUG] client config:", clientDeclInConfig)
+	return nil
 }
 
 // 解析资源文件的主入口
 func parseResourceFile(resourceName string, filePath string, file *ast.File, fset *token.FileSet, publicFuncs []string,
-	newResourceName string) (resourceName2 string, description string, allURI []CloudUri, rpath string, newResourceName2 string) {
-
-	sdkFilePreFix := "github.com/chnsz/golangsdk/openstack/"
-	//先找到使用SDK的地方
-	sdkPackages := make(map[string]string) //key： 先取别名>包名
-	for _, d := range file.Imports {
-		fullPath := strings.Trim(d.Path.Value, `"`)
-		if strings.Contains(fullPath, sdkFilePreFix) {
-			alias := fullPath[strings.LastIndex(fullPath, "/")+1:]
-			if d.Name != nil {
-				alias = d.Name.Name
-			}
-			sdkPackages[alias] = fullPath
-		}
-	}
+	newResourceName string) (resourceName2 string, description string, allURI []CloudUri, rpath string, newResourceName2 string,
+	err error) {
+
+	//先找到使用SDK的地方, 每个导入的包单独记录
+	sdkPackages := parseSdkImports(file, golangsdkPrefix)
 
 	resourceFilebytes, err := ioutil.ReadFile(filePath)
 	if err != nil {
-		log.Fatal(err)
+		return resourceName, "", nil, filePath, newResourceName, err
 	}
 
 	allResourceFileFunc := findAllFunc(file, fset)
 
 	allURI = findAllURI(sdkPackages, resourceFilebytes, allResourceFileFunc, fset, publicFuncs)
-	//fmt.Println(allURI)
 
-	return resourceName, "", allURI, filePath, newResourceName
+	return resourceName, resourceDescription(newResourceName, file), allURI, filePath, newResourceName, nil
 }
 
-func findAllURI(sdkPackages map[string]string, resourceFileBytes []byte, funcDecls []*ast.FuncDecl, fset *token.FileSet, publicFuncs []string) (r []CloudUri) {
+func findAllURI(sdkPackages []sdkImport, resourceFileBytes []byte, funcDecls []*ast.FuncDecl, fset *token.FileSet, publicFuncs []string) (r []CloudUri) {
 	rt := []CloudUri{}
 
 	//key: funcName:clientDeclName
@@ -107,7 +97,7 @@ func findAllURI(sdkPackages map[string]string, resourceFileBytes []byte, funcDec
 	return removeDuplicateCloudUri(rt)
 }
 
-func findAllUriFromResourceFunc(curResourceFuncDecl *ast.FuncDecl, sdkPackages map[string]string,
+func findAllUriFromResourceFunc(curResourceFuncDecl *ast.FuncDecl, sdkPackages []sdkImport,
 	resourceFileBytes []byte, funcDecls []*ast.FuncDecl, fset *token.FileSet, publicFuncs []string) []CloudUri {
 
 	funcName := curResourceFuncDecl.Name.Name
@@ -116,11 +106,13 @@ func findAllUriFromResourceFunc(curResourceFuncDecl *ast.FuncDecl, sdkPackages m
 	funcSrc := string(resourceFileBytes[startIndex:endIndex])
 
 	cloudUriArray := []CloudUri{}
-	for alias, sdkFilePath := range sdkPackages {
-		// 根据import的别名，匹配使用到的地方
+	for _, imp := range sdkPackages {
+		alias, sdkFilePath := imp.localName, imp.path
+		// 根据import的别名或包名，匹配使用到的地方, 名称前不能是标识符或者 . (eg: resourcetags.Get, d.tags.Get)
 		//1. client在前面定义的 eg: refinedAntiddos, err := antiddos.ListStatus(antiddosClient, listStatusOpts)
-		reg := regexp.MustCompile(fmt.Sprintf(`(%s)\.(\w*)\((\w*)(.*)`, alias))
+		reg := regexp.MustCompile(fmt.Sprintf(`(?:^|[^\w.])(%s)\.(\w*)\((\w*)(.*)`, regexp.QuoteMeta(alias)))
 		allSubMatch := reg.FindAllStringSubmatch(funcSrc, -1)
+		allSubMatchIndex := reg.FindAllStringSubmatchIndex(funcSrc, -1)
 		for i := 0; i < len(allSubMatch); i++ {
 			//0:全部字符串，1：第一个submatch ...
 			//methodInvokeIndexStart, methodInvokeIndexEnd := allSubMatchIndex[i][0], allSubMatchIndex[i][1]
@@ -132,43 +124,72 @@ func findAllUriFromResourceFunc(curResourceFuncDecl *ast.FuncDecl, sdkPackages m
 			clientBeenUsed := allSubMatch[i][3]
 
 			if strings.HasPrefix(sdkFunctionName, "Extract") {
-				log.Printf("[DEBUG] skip to parse %s.%s\n", alias, sdkFunctionName)
+				logDebug("skip the extract function", "sdk_func", alias+"."+sdkFunctionName)
 				continue
 			}
 
-			log.Printf("find function %s used %s.%s with %s\n", funcName, alias, sdkFunctionName, clientBeenUsed)
-			cloudUri := parseUriFromSdk(sdkFilePath, sdkFunctionName)
+			logDebug("found the SDK call", "func", funcName, "sdk_func", alias+"."+sdkFunctionName, "client", clientBeenUsed)
+			cloudUris := parseUriFromSdk(sdkFilePath, sdkFunctionName)
 			//只有在sdk中匹配到的，才是有效的
-			if cloudUri.url != "" {
+			if len(cloudUris) > 0 {
 				//2. 根据这里使用到的client ，向上找最近的一个 serviceClient定义,并根据它找到 resourceType,version等信息
+				resourceType := "unknown"
+				var serviceCatalog config.ServiceCatalog
 				clientName, err := parseClientDecl(string(clientBeenUsed), funcSrc, curResourceFuncDecl, resourceFileBytes, funcDecls, fset)
 				if err != nil {
-					log.Printf("[WARN] found none client declares %s, so skip %s\n", clientBeenUsed, funcName)
-					cloudUri.resourceType = "unknown"
+					logWarn("client declaration not found", "func", funcName, "sdk_func", alias+"."+sdkFunctionName,
+						"client", clientBeenUsed, "reason", err)
 				} else {
 					//在config.go中获得 catgegoryName
 					categoryName := getCategoryFromConfig(clientName)
-					log.Printf("[DEBUG] service category of %s is %s", clientName, categoryName)
+					logDebug("found the service category", "client", clientName, "category", categoryName)
 
 					if serviceCategory := parseEndPointByClient(categoryName); serviceCategory != nil {
-						cloudUri.resourceType = serviceCategory.Name
-						cloudUri.serviceCatalog = *serviceCategory
+						resourceType = serviceCategory.Name
+						serviceCatalog = *serviceCategory
 					} else {
-						log.Printf("[ERROR] can not find service catalog of %s\n", categoryName)
+						resourceType = ""
+						logError("service catalog not found", "client", clientName, "category", categoryName)
 					}
 				}
 
-				// 特殊处理 golangsdk/openstack/common/tags 包的调用
-				// 1. 替换 {resourceType} 变量
-				// 2. 在URL中增加projectID --- WithOutProjectID = false
-				newCloudUri := replaceTagUri(allSubMatch[i], cloudUri.url)
-				if newCloudUri != "" {
-					cloudUri.url = newCloudUri
-					cloudUri.serviceCatalog.WithOutProjectID = false
+				// 一个SDK函数可能发起多个请求
+				callPos := curResourceFuncDecl.Pos() + token.Pos(allSubMatchIndex[i][2])
+				source := sourcePosition(fset, callPos)
+				polling := callPolling(curResourceFuncDecl, callPos)
+				callUris := []CloudUri{}
+				forceProjectID := false
+				for _, cloudUri := range cloudUris {
+					cloudUri.resourceType = resourceType
+					cloudUri.serviceCatalog = serviceCatalog
+					cloudUri.sdkFunction = sdkFunctionName
+					cloudUri.terraformFunction = funcName
+					cloudUri.source = source
+					cloudUri.polling = polling
+
+					// 特殊处理 golangsdk/openstack/common/tags 包的调用
+					// 1. 替换 {resource_type} 变量
+					// 2. 在URL中增加projectID --- WithOutProjectID = false
+					newCloudUri := replaceTagUri(sdkFilePath, allSubMatch[i], cloudUri.url)
+					if newCloudUri != "" {
+						cloudUri.url = newCloudUri
+						cloudUri.serviceCatalog.WithOutProjectID = false
+						forceProjectID = true
+					}
+					callUris = append(callUris, cloudUri)
 				}
-				cloudUriArray = append(cloudUriArray, cloudUri)
+				cloudUriArray = append(cloudUriArray, dropProjectIDCandidates(callUris, forceProjectID)...)
+			} else if !isSdkRequestFunc(sdkFilePath, sdkFunctionName) {
+				logDebug("skip the call which does not send requests", "func", funcName,
+					"sdk_func", alias+"."+sdkFunctionName, "sdk_package", sdkFilePath)
 			} else {
-				log.Println("parseUriFromSdk.return empty", sdkFunctionName, sdkFilePath)
+				logWarn("unresolved SDK call", "func", funcName, "sdk_func", alias+"."+sdkFunctionName,
+					"sdk_package", sdkFilePath, "reason", "no URL found in the SDK package")
+				recorder.addUnresolvedCall(unresolvedCall{
+					Func:       funcName,
+					SdkFunc:    alias + "." + sdkFunctionName,
+					SdkPackage: sdkFilePath,
+				})
 			}
 		}
 		// TODO: client 直接定义在方法里
@@ -180,18 +201,18 @@ func findAllUriFromResourceFunc(curResourceFuncDecl *ast.FuncDecl, sdkPackages m
 	return cloudUriArray
 }
 
-func replaceTagUri(allSubMatch []string, url string) string {
-	if allSubMatch[1] == "tags" && len(allSubMatch) > 4 {
-		log.Printf("[DEBUG] parse tags URL in `%s`\n", allSubMatch[0])
+func replaceTagUri(sdkFilePath string, allSubMatch []string, url string) string {
+	if sdkFilePath == golangsdkPrefix+"common/tags" && len(allSubMatch) > 4 {
+		logDebug("parse the tags URL", "code", allSubMatch[0])
 		reg := regexp.MustCompile(`,\s"(.*)",`)
 		subMatch := reg.FindStringSubmatch(allSubMatch[4])
 		if len(subMatch) > 1 {
 			serviceTag := subMatch[1]
-			newUrl := strings.Replace(url, "{resourceType}", serviceTag, -1)
-			log.Printf("[DEBUG] update tags URL from %s to %s\n", url, newUrl)
+			newUrl := strings.Replace(url, "{resource_type}", serviceTag, -1)
+			logDebug("update the tags URL", "url", url, "target", newUrl)
 			return newUrl
 		}
-		log.Printf("[DEBUG] the tags URL(%s) is not changed\n", url)
+		logDebug("the tags URL is not changed", "url", url)
 		return url
 	}
 
@@ -205,13 +226,21 @@ func parseTagUriInFunc(funcSrc string, curResourceFuncDecl *ast.FuncDecl, resour
 	// utils.UpdateResourceTags(computeClient, d, "cloudservers", serverId)
 	reg := regexp.MustCompile(`utils\.UpdateResourceTags\((\w*),\s(\w*),\s"(.*)",\s(.*)\)`)
 	allSubMatch := reg.FindAllStringSubmatch(funcSrc, -1)
+	allSubMatchIndex := reg.FindAllStringSubmatchIndex(funcSrc, -1)
 	if len(allSubMatch) > 0 {
-		log.Printf("[DEBUG] parse tags URL in `%s`\n", allSubMatch[0][0])
+		logDebug("parse the tags URL", "code", allSubMatch[0][0])
 
 		clientBeenUsed := allSubMatch[0][1]
 		serviceType := allSubMatch[0][3]
 		tagUri := []CloudUri{
-			{url: serviceType + "/{id}/tags/action", httpMethod: "POST", operationId: "batchUpdate"},
+			{
+				url:               serviceType + "/{id}/tags/action",
+				httpMethod:        "post",
+				operationId:       qualifiedOperationId(golangsdkPrefix+"common/tags", "batchUpdate"),
+				sdkFunction:       "utils.UpdateResourceTags",
+				terraformFunction: curResourceFuncDecl.Name.Name,
+				source:            sourcePosition(fset, curResourceFuncDecl.Pos()+token.Pos(allSubMatchIndex[0][0])),
+			},
 		}
 
 		for _, cloudUri := range tagUri {
@@ -220,12 +249,13 @@ func parseTagUriInFunc(funcSrc string, curResourceFuncDecl *ast.FuncDecl, resour
 				clientName, err := parseClientDecl(string(clientBeenUsed), funcSrc, curResourceFuncDecl, resourceFileBytes, funcDecls, fset)
 				if err != nil {
 					funcName := curResourceFuncDecl.Name.Name
-					log.Printf("[WARN] found none client declares %s, so skip %s\n", clientBeenUsed, funcName)
+					logWarn("client declaration not found", "func", funcName, "sdk_func", "utils.UpdateResourceTags",
+						"client", clientBeenUsed, "reason", err)
 					cloudUri.resourceType = "unknown"
 				} else {
 					//在config.go中获得 catgegoryName
 					categoryName := getCategoryFromConfig(clientName)
-					log.Printf("[DEBUG] service category of %s is %s", clientName, categoryName)
+					logDebug("found the service category", "client", clientName, "category", categoryName)
 
 					if serviceCategory := parseEndPointByClient(categoryName); serviceCategory != nil {
 						// 特殊处理 golangsdk/openstack/common/tags 包的调用
@@ -234,7 +264,7 @@ func parseTagUriInFunc(funcSrc string, curResourceFuncDecl *ast.FuncDecl, resour
 						cloudUri.resourceType = serviceCategory.Name
 						cloudUri.serviceCatalog = *serviceCategory
 					} else {
-						log.Printf("[ERROR] can not find service catalog of %s\n", categoryName)
+						logError("service catalog not found", "client", clientName, "category", categoryName)
 					}
 				}
 
@@ -246,23 +276,43 @@ func parseTagUriInFunc(funcSrc string, curResourceFuncDecl *ast.FuncDecl, resour
 	return cloudUriArray
 }
 
-func parseUriFromSdk(sdkFilePath string, sdkFunctionName string) (r CloudUri) {
-	//TODO 从 vendor/github.com/terraform-providers/golangsdk/openstack/deh/v1/hosts/requests.go
-	sdkFileDir := "./vendor/" + sdkFilePath + "/"
-
-	cUri := getUriFromRequestFile(sdkFileDir, sdkFunctionName, true)
-	fmt.Println("mmmm", cUri.url, cUri.httpMethod, sdkFunctionName, sdkFileDir)
+func parseUriFromSdk(sdkFilePath string, sdkFunctionName string) []CloudUri {
+	// 从 vendor/github.com/chnsz/golangsdk/openstack/deh/v1/hosts/ 中解析
+	sdkFileDir := basePath + "vendor/" + sdkFilePath + "/"
 
-	// 去除URL中的query参数
-	if lastIndex := strings.Index(cUri.url, "?"); lastIndex > 0 {
-		cUri.url = cUri.url[:lastIndex]
+	// SDK函数注释的第一句作为 summary
+	var summary string
+	if evaluator, err := getURLEvaluator(sdkFileDir); err == nil {
+		if fn, ok := evaluator.funcs[sdkFunctionName]; ok {
+			summary = docSummary(fn.Doc, "")
+		}
 	}
 
-	r.url = cUri.url
-	r.httpMethod = cUri.httpMethod
-	r.operationId = sdkFunctionName
-	r.filePath = sdkFilePath
-	return r
+	rst := []CloudUri{}
+	for _, cUri := range getUriFromRequestFile(sdkFileDir, sdkFunctionName, true) {
+		logDebug("resolved the SDK call", "sdk_func", sdkFunctionName, "sdk_package", sdkFilePath,
+			"method", cUri.httpMethod, "url", cUri.url)
+
+		// 去除URL中的query参数, query参数从 opts 的 q 标签中获取
+		if lastIndex := strings.Index(cUri.url, "?"); lastIndex > 0 {
+			cUri.url = cUri.url[:lastIndex]
+		}
+
+		rst = append(rst, CloudUri{
+			url:          snakePathPlaceholders(cUri.url),
+			httpMethod:   cUri.httpMethod,
+			operationId:  qualifiedOperationId(sdkFilePath, sdkFunctionName),
+			summary:      summary,
+			filePath:     sdkFilePath,
+			parameters:   cUri.parameters,
+			requestBody:  cUri.requestBody,
+			responseBody: cUri.responseBody,
+			statusCodes:  cUri.statusCodes,
+			action:       cUri.action,
+			pagination:   cUri.pagination,
+		})
+	}
+	return qualifyOperations(rst)
 }
 
 func parseClientDecl(clientBeenUsed string, funcSrc string, curResourceFuncDecl *ast.FuncDecl, resourceFileBytes []byte, funcDecls []*ast.FuncDecl, fset *token.FileSet) (string, error) {
@@ -296,7 +346,6 @@ func parseClientDecl(clientBeenUsed string, funcSrc string, curResourceFuncDecl
 			if specailClient != "" {
 				return specailClient, nil
 			}
-			fmt.Println("没有到找到定义serviceClient的地方", clientBeenUsed, funcSrc)
 			return "", fmt.Errorf("cannot found the client %s in function %s body", clientBeenUsed, funcName)
 		}
 
@@ -334,19 +383,18 @@ func parseMethodbeenInvoke(funcName string, argsIndex int, resourceFileBytes []b
 		if len(allSubMatch) > 0 {
 			argStr := allSubMatch[0][1]
 			calledFuncName := curResourceFuncDecl.Name.Name
-			fmt.Printf("parseMethodbeenInvoke.agrs: %s call %s(%s) %d\n", calledFuncName, funcName, argStr, argsIndex)
+			logDebug("found the caller of function", "func", calledFuncName, "callee", funcName, "args", argStr)
 			args := strings.Split(argStr, ",")
 			if len(args) >= argsIndex {
 				arg := strings.Trim(args[argsIndex-1], " ")
 				arg = strings.Trim(arg, ")")
 				// 解析失败，直接return
 				if strings.Contains(arg, "(") {
-					log.Printf("[WARN] unable to parse the arguments in %s %s\n", calledFuncName, arg)
+					logWarn("unable to parse the arguments", "func", calledFuncName, "args", arg)
 					return
 				}
 
 				clientBeenUsed = arg
-				fmt.Println("parseMethodbeenInvoke.agrs2", calledFuncName, clientBeenUsed)
 				exist = true
 				return
 			}
@@ -356,347 +404,338 @@ func parseMethodbeenInvoke(funcName string, argsIndex int, resourceFileBytes []b
 	return
 }
 
-func parseUriFromUriFile(filePath string) {
-	set := token.NewFileSet()
-	f, err := parser.ParseFile(set, filePath, nil, 0)
+// isSdkRequestFunc 判断SDK包中的名称是否可能发起请求, 类型转换和不使用 ServiceClient 的辅助函数不会发起请求
+// eg: pools.LBMethod(...), obs.StorageClassType(...)
+func isSdkRequestFunc(sdkFilePath, name string) bool {
+	evaluator, err := getURLEvaluator(basePath + "vendor/" + sdkFilePath + "/")
 	if err != nil {
-		log.Println("Failed to parse file:", filePath, err)
-		return
-	}
-
-	//fmt.Printf("func: %v", getFuncList(f, ast.Con, true))
-
-	varInPacks := make(map[string]string)
-	for _, object := range f.Scope.Objects {
-		if object.Kind == ast.Var || object.Kind == ast.Con {
-			valueDecls := object.Decl.(*ast.ValueSpec)
-			for i := 0; i < len(valueDecls.Names); i++ {
-				name := valueDecls.Names[i].Name
-				if len(valueDecls.Values) > i {
-					//只认基础字符类型
-					vExpr := valueDecls.Values[i].(*ast.BasicLit)
-					varInPacks[name] = strings.ReplaceAll(vExpr.Value, `"`, ``)
-				}
-			}
-			//ast.Print(set, object)
+		return true
+	}
+	if _, ok := evaluator.types[name]; ok {
+		return false
+	}
+	if fn, ok := evaluator.funcs[name]; ok {
+		return hasServiceClientParam(fn)
+	}
+	return true
+}
+
+// hasServiceClientParam 判断函数是否有 *golangsdk.ServiceClient 类型的参数
+func hasServiceClientParam(fn *ast.FuncDecl) bool {
+	for _, field := range fn.Type.Params.List {
+		expr := field.Type
+		if star, ok := expr.(*ast.StarExpr); ok {
+			expr = star.X
+		}
+		if sel, ok := expr.(*ast.SelectorExpr); ok && sel.Sel.Name == "ServiceClient" {
+			return true
 		}
 	}
+	return false
+}
 
-	resourceFilebytes, err := ioutil.ReadFile(filePath)
-	if err != nil {
-		log.Fatal(err)
+func getUriFromRequestFile(sdkFileDir string, funcName string, isParsefile bool) []CloudUri {
+	if v, ok := urlSupportsInRequestFile[sdkFileDir+"."+funcName]; ok {
+		return v
 	}
 
-	// 用于保存调用其他URL方法的函数
-	callingFunc := make(map[string]string)
+	if isParsefile {
+		if err := parseUriFromRequestFile(sdkFileDir); err != nil {
+			reportScanError(stageSDK, sdkFileDir, err)
+		}
+		return getUriFromRequestFile(sdkFileDir, funcName, false)
+	}
 
-	for _, d := range f.Decls {
-		if fn, isFn := d.(*ast.FuncDecl); isFn {
-			startIndex := set.Position(fn.Pos()).Offset
-			endIndex := set.Position(fn.End()).Offset
+	logWarn("failed to parse the request function", "sdk_func", funcName, "sdk_package", sdkFileDir)
+	return nil
+}
 
-			funcSrc := string(resourceFilebytes[startIndex:endIndex])
-			funcName := fn.Name.Name
-			funckey := filePath + "." + funcName
-
-			//判断是否有 client.ServiceURL(resourcePath, id, passwordPath)
-			reg := regexp.MustCompile(`\.ServiceURL\((.*)\)`)
-			submatch := reg.FindAllStringSubmatch(funcSrc, 1) //只取第一个
-
-			argStartIndex := 0
-			//这里有个奇葩的 cce，特殊处理下,argStartIndex 需要偏移2位
-
-			if len(submatch) == 0 {
-				reg := regexp.MustCompile(`return\s.*CCEServiceURL\((.*)\)`)
-				submatch2 := reg.FindAllStringSubmatch(funcSrc, 1) //只取第一个
-				if len(submatch2) > 0 {
-					log.Printf("cceClient: %v, %s\n", submatch2, funcName)
-					submatch = submatch2
-					argStartIndex = 2
-				}
-			}
+// parseUriFromRequestFile 索引SDK包中所有非测试文件的HTTP请求, 请求和URL函数可以分布在不同的文件中
+// 一个函数可以发起多个请求, 也可以通过调用包内的其他函数间接发起请求
+func parseUriFromRequestFile(sdkFileDir string) error {
+	evaluator, err := getURLEvaluator(sdkFileDir)
+	if err != nil {
+		return err
+	}
+	if len(evaluator.fileNames) == 0 {
+		return fmt.Errorf("can not find any Go file in %s", sdkFileDir)
+	}
 
-			if len(submatch) == 0 {
-				regSp := regexp.MustCompile(`return\s(\w+)\(`)
-				submatch2 := regSp.FindAllStringSubmatch(funcSrc, 1) //只取第一个
-				if len(submatch2) > 0 {
-					name := submatch2[0][1]
-					callingFunc[funckey] = name
-				} else {
-					log.Printf("[WARN] can not parse the URL function %s in %s\n", funcName, filePath)
+	// key: 函数名称, value: 函数构造的请求体的根节点名称
+	actionKeys := make(map[string]string)
+	allFuncs := evaluator.allFuncs()
+	for _, fn := range allFuncs {
+		funcName := fn.Name.Name
+		key := sdkFileDir + "." + funcName
+		// ListOpts 等参数中定义的query参数, CreateOpts 等参数中定义的请求体, XxxResult 中解析的响应体
+		queryParams := evaluator.queryParameters(fn)
+		requestBody := evaluator.requestBodySchema(fn)
+		responseBody := evaluator.responseBodySchema(fn)
+		actionKeys[funcName] = evaluator.actionKey(fn, requestBody)
+		// key: client 方法调用在函数源码中的位置
+		okCodes := evaluator.okCodes(fn)
+
+		// clientMethod: client 的方法名称, eg: Post, DeleteWithBody
+		// pagination: pagination.NewPager 的分页方式, 其他请求为空
+		addCandidate := func(clientMethod, uri string, codes []int, pagination string) {
+			cloudUri := CloudUri{
+				url:          uri,
+				httpMethod:   mapToStandardHttpMethod(clientMethod),
+				parameters:   queryParams,
+				responseBody: responseBody,
+				statusCodes:  codes,
+				pagination:   pagination,
+			}
+			if len(codes) == 0 {
+				cloudUri.statusCodes = defaultOkCodes(cloudUri.httpMethod)
+			}
+			if hasRequestBody(clientMethod) {
+				cloudUri.requestBody = requestBody
+				if isActionUrl(uri) {
+					cloudUri.action = actionKeys[funcName]
 				}
-				continue
 			}
-
-			paramStr := submatch[0][1]
-			params := strings.Split(paramStr, ",")
-			paramValues := []string{}
-			for j := argStartIndex; j < len(params); j++ {
-				key := strings.TrimSpace(params[j])
-				isString := strings.Contains(key, `"`)
-				key = strings.ReplaceAll(key, `"`, ``)
-
-				if isString {
-					paramValues = append(paramValues, key)
+			urlSupportsInRequestFile[key] = appendCloudUri(urlSupportsInRequestFile[key], cloudUri)
+		}
+		// 请求的URL参数可以是URL函数、URL变量或者直接构造的URL, 统一通过 urlEvaluator 计算
+		for _, call := range evaluator.requestCalls(fn) {
+			logDebug("found the HTTP request", "sdk_func", funcName, "method", call.clientMethod, "urls", call.urls)
+			// URL函数的条件分支可能返回不同的URL, 每个都作为一个请求
+			for _, uri := range call.urls {
+				if call.clientMethod == "NewPager" {
+					addCandidate("Get", uri, nil, evaluator.pagerPagination(fn, queryParams))
 				} else {
-					v, ok := varInPacks[key]
-					if ok || isString {
-						paramValues = append(paramValues, v)
-					} else {
-						if strings.HasSuffix(key, ".ProjectID") {
-							key = "project_id"
-						}
-						paramValues = append(paramValues, fmt.Sprintf(`{%s}`, key))
-					}
-
+					addCandidate(call.clientMethod, uri, okCodes[call.offset], "")
 				}
 			}
-			uri := strings.Join(paramValues, "/")
-			urlSupportsInUriFile[funckey] = uri
-
-			//处理特殊URL
-			if strings.Contains(funckey, "/dns/v2/ptrrecords/urls.go.baseURL") ||
-				strings.Contains(funckey, "/dns/v2/ptrrecords/urls.go.resourceURL") {
-				urlSupportsInUriFile[funckey] = "reverse/floatingips/{region}:{floatingip_id}"
-			}
 		}
 	}
 
-	// 处理函数调用的情况
-	for key, funcName := range callingFunc {
-		uri := urlSupportsInUriFile[filePath+"."+funcName]
-		urlSupportsInUriFile[key] = uri
-		if uri == "" {
-			log.Printf("[WARN] can not parse the URL function %s/%s in %s\n", key, funcName, filePath)
-		}
-	}
+	// 处理间接调用
+	parseRequestFuncNotDirect(sdkFileDir, allFuncs, actionKeys)
+	return nil
 }
 
-/*
-先从map中获取，没有则重新解析文件
-*/
-func getUriFromUriFile(filePath string, funcName string, isParsefile bool) string {
-	if v, ok := urlSupportsInUriFile[filePath+"."+funcName]; ok {
-		return v
+// parseRequestFuncNotDirect 将被调用函数的请求合并到调用者中, 直到没有新的请求, 支持多层的间接调用
+// eg: ForceDelete -> Delete -> client.Post(deleteURL(c), ...)
+// 被调用函数的 .../action 请求没有确定操作时, 使用调用者构造的请求体的根节点, eg: Stop -> serverAction
+func parseRequestFuncNotDirect(sdkFileDir string, allFuncs []*ast.FuncDecl, actionKeys map[string]string) {
+	callees := make(map[string][]string)
+	for _, fn := range allFuncs {
+		if fn.Body == nil {
+			continue
+		}
+		funcName := fn.Name.Name
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok {
+				if ident, ok := call.Fun.(*ast.Ident); ok && ident.Name != funcName {
+					callees[funcName] = append(callees[funcName], ident.Name)
+				}
+			}
+			return true
+		})
 	}
 
-	if isParsefile {
-		parseUriFromUriFile(filePath)
-		return getUriFromUriFile(filePath, funcName, false)
+	for changed := true; changed; {
+		changed = false
+		for _, fn := range allFuncs {
+			funcName := fn.Name.Name
+			key := sdkFileDir + "." + funcName
+			for _, callee := range callees[funcName] {
+				for _, v := range urlSupportsInRequestFile[sdkFileDir+"."+callee] {
+					if isActionUrl(v.url) && v.action == "" {
+						v.action = actionKeys[funcName]
+					}
+					before := len(urlSupportsInRequestFile[key])
+					urlSupportsInRequestFile[key] = appendCloudUri(urlSupportsInRequestFile[key], v)
+					if len(urlSupportsInRequestFile[key]) > before {
+						logDebug("found the indirect HTTP request", "sdk_func", funcName, "callee", callee,
+							"method", v.httpMethod, "url", v.url)
+						changed = true
+					}
+				}
+			}
+		}
 	}
-
-	log.Printf("[WARN] failed parsing URL of method %s in file %s\n", funcName, filePath)
-	return ""
 }
 
-func getUriFromRequestFile(sdkFileDir string, funcName string, isParsefile bool) CloudUri {
-	if v, ok := urlSupportsInRequestFile[sdkFileDir+"."+funcName]; ok {
-		return v
-	}
-
-	if isParsefile {
-		parseUriFromRequestFile(sdkFileDir)
-		return getUriFromRequestFile(sdkFileDir, funcName, false)
+// hasRequestBody 判断 client 的方法是否发送请求体
+func hasRequestBody(clientMethod string) bool {
+	switch clientMethod {
+	case "Post", "Put", "Patch", "DeleteWithBody", "DeleteWithBodyResp":
+		return true
 	}
-
-	log.Printf("[WARN] failed parsing cloud URL of method %s in package %s\n", funcName, sdkFileDir)
-	return CloudUri{}
+	return false
 }
 
-func parseUriFromRequestFile(sdkFileDir string) {
-	set := token.NewFileSet()
-	// most of all files are named requests.go
-	// request.go is only in openstack/elb/v2/certificates package, will normalize it in golansdk
-	requestFileNames := []string{"requests.go", "request.go"}
-	var requestFilePath string
-
-	for _, v := range requestFileNames {
-		if filePathExists(sdkFileDir + v) {
-			requestFilePath = sdkFileDir + v
-			break
-		}
-	}
+// builtinOkCodes 无法解析 vendor 中的 golangsdk 时使用的默认状态码, 与 chnsz/golangsdk 的 defaultOkCodes 一致
+var builtinOkCodes = map[string][]int{
+	"get":    {200},
+	"head":   {204},
+	"post":   {200, 201, 202},
+	"put":    {200, 201, 202},
+	"patch":  {200, 202, 204},
+	"delete": {200, 202, 204},
+}
 
-	if requestFilePath == "" {
-		log.Println("[ERROR] cant find the requests files in ", sdkFileDir)
-		return
-	}
+// okCodeTables 保存已经解析的默认状态码, key: provider 的路径
+var okCodeTables = make(map[string]map[string][]int)
 
-	f, err := parser.ParseFile(set, requestFilePath, nil, 0)
-	if err != nil {
-		log.Println("Failed to parse file:", requestFilePath, err)
-		return
+// defaultOkCodes 没有指定 OkCodes 时 golangsdk 接受的状态码, 从 vendor 中的 provider_client.go 解析
+func defaultOkCodes(httpMethod string) []int {
+	table, ok := okCodeTables[basePath]
+	if !ok {
+		table = parseDefaultOkCodes(basePath + "vendor/github.com/chnsz/golangsdk/provider_client.go")
+		okCodeTables[basePath] = table
 	}
+	return table[httpMethod]
+}
 
-	resourceFilebytes, err := ioutil.ReadFile(requestFilePath)
+// parseDefaultOkCodes 解析 golangsdk 中 defaultOkCodes 函数的 switch 语句
+// eg: case method == "POST": return []int{200, 201, 202}
+func parseDefaultOkCodes(filePath string) map[string][]int {
+	file, err := parser.ParseFile(token.NewFileSet(), filePath, nil, 0)
 	if err != nil {
-		log.Fatal(err)
+		logWarn("can not parse the default OK codes of golangsdk, use the built-in ones", "file", filePath,
+			"reason", err)
+		return builtinOkCodes
 	}
 
-	//fmt.Printf("func: %v", getFuncList(f, ast.Con, true))
-	funcNotDirectUseURLs := []*ast.FuncDecl{}
-	urlSupportsInCurrentFile := []string{}
-
-	var uriFilePath string
-	// most of all files are named urls.go
-	// url.go and utils.go are located in some packages, will normalize them in golansdk
-	urlsFileNames := []string{"urls.go", "url.go", "utils.go"}
-	for _, v := range urlsFileNames {
-		if filePathExists(sdkFileDir + v) {
-			uriFilePath = sdkFileDir + v
-			break
+	table := make(map[string][]int)
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != "defaultOkCodes" || fn.Body == nil {
+			continue
 		}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			clause, ok := n.(*ast.CaseClause)
+			if !ok {
+				return true
+			}
+			var codes []int
+			for _, stmt := range clause.Body {
+				if ret, ok := stmt.(*ast.ReturnStmt); ok && len(ret.Results) == 1 {
+					if lit, ok := ret.Results[0].(*ast.CompositeLit); ok {
+						codes = statusCodesOfLit(lit)
+					}
+				}
+			}
+			for _, expr := range clause.List {
+				// case "POST": 或者 case method == "POST":
+				if binary, ok := expr.(*ast.BinaryExpr); ok && binary.Op == token.EQL {
+					expr = binary.Y
+				}
+				if basic, ok := expr.(*ast.BasicLit); ok && basic.Kind == token.STRING && codes != nil {
+					table[strings.ToLower(strings.Trim(basic.Value, `"`))] = codes
+				}
+			}
+			return false
+		})
 	}
 
-	if uriFilePath == "" {
-		log.Println("[ERROR] cant find the url files", sdkFileDir)
-		return
+	if len(table) == 0 {
+		logWarn("defaultOkCodes is not found in golangsdk, use the built-in OK codes", "file", filePath)
+		return builtinOkCodes
 	}
+	return table
+}
 
-	for _, d := range f.Decls {
-		if fn, isFn := d.(*ast.FuncDecl); isFn {
-			startIndex := set.Position(fn.Pos()).Offset
-			endIndex := set.Position(fn.End()).Offset
-
-			funcSrc := string(resourceFilebytes[startIndex:endIndex])
-			funcName := fn.Name.Name
-
-			//判断是否有 client.Post(createURL(client), b)
-			reg1 := regexp.MustCompile(`\.(Head|Get|Post|Put|Patch|Delete|DeleteWithBody|DeleteWithResponse|DeleteWithBodyResp)\((\w*)\(`)
-			//判断是否是client.Put(updateURL, b）
-			reg2 := regexp.MustCompile(`\.(Head|Get|Post|Put|Patch|Delete|DeleteWithBody|DeleteWithResponse|DeleteWithBodyResp)\((\w*),`)
-
-			//判断是否是 pagination.NewPager(c, rootURL(c),
-			reg3 := regexp.MustCompile(`NewPager\(\w*,\s*(\w*)\(`)
-			//判断是否是 pagination.NewPager(c, u,
-			reg4 := regexp.MustCompile(`NewPager\(\w*,\s*(\w*),`)
-
-			submatch1 := reg1.FindAllStringSubmatch(funcSrc, -1)
-			submatch2 := reg2.FindAllStringSubmatch(funcSrc, -1)
-			submatch3 := reg3.FindAllStringSubmatch(funcSrc, -1)
-			submatch4 := reg4.FindAllStringSubmatch(funcSrc, -1)
-
-			urlDeclInFunc := false
-
-			if len(submatch1) > 0 {
-				urlDeclInFunc = true
-				for i := 0; i < len(submatch1); i++ {
-					httpMethod := submatch1[i][1]
-					urlFunc := submatch1[i][2]
-					//判断
-					//	mapToUrl(urlFunc,urlFilePath)
-					//	println("ososo:", httpClientMethod, urlFunc)
-					log.Println("[DEBUG] parseUriFromRequestFile-regmatch1", funcName, urlFunc)
-					//	fmt.Println("request path:", filePath, "uriFilePath:", uriFilePath)
-					uri := getUriFromUriFile(uriFilePath, urlFunc, true)
-					cloudUri := new(CloudUri)
-					cloudUri.url = uri
-					cloudUri.httpMethod = mapToStandardHttpMethod(httpMethod)
-					urlSupportsInRequestFile[sdkFileDir+"."+funcName] = *cloudUri
-					urlSupportsInCurrentFile = append(urlSupportsInCurrentFile, funcName)
-				}
-			} else if len(submatch2) > 0 {
-				urlDeclInFunc = true
-				for i := 0; i < len(submatch2); i++ {
-					httpMethod := submatch2[i][1]
-					urlFunc := submatch2[i][2]
-					log.Println("[DEBUG] parseUriFromRequestFile-regmatch2", funcName, urlFunc)
-
-					regUrLDecl := regexp.MustCompile(fmt.Sprintf(`%s\s*:=\s*(\w*)`, urlFunc))
-					urlDeclsMatch := regUrLDecl.FindAllStringSubmatch(funcSrc, 1)
-					if len(urlDeclsMatch) > 0 {
-						urlFunc = urlDeclsMatch[0][1]
-						uri := getUriFromUriFile(uriFilePath, urlFunc, true)
-						cloudUri := new(CloudUri)
-						cloudUri.url = uri
-						cloudUri.httpMethod = mapToStandardHttpMethod(httpMethod)
-						urlSupportsInRequestFile[sdkFileDir+"."+funcName] = *cloudUri
-						urlSupportsInCurrentFile = append(urlSupportsInCurrentFile, funcName)
-					} else {
-						log.Println("[ERROR] failed find URL decl in request.go", fn.Name.Name, urlFunc)
-					}
+// okCodes 解析 client 方法调用中 RequestOpts 的 OkCodes, key 为调用的 .Post 等在函数源码中的位置
+// eg: client.Post(url, b, &r.Body, &golangsdk.RequestOpts{OkCodes: []int{200}})
+// 或者 reqOpt := &golangsdk.RequestOpts{OkCodes: []int{201}}; client.Post(url, b, &r.Body, reqOpt)
+func (e *urlEvaluator) okCodes(fn *ast.FuncDecl) map[int][]int {
+	rst := make(map[int][]int)
+	if fn.Body == nil {
+		return rst
+	}
 
-				}
-			} else if len(submatch3) > 0 {
-				urlDeclInFunc = true
-				for i := 0; i < len(submatch3); i++ {
-					httpMethod := "get"
-					urlFunc := submatch3[i][1]
-					//判断
-					//	mapToUrl(urlFunc,urlFilePath)
-					//	println("ososo:", httpClientMethod, urlFunc)
-					log.Println("[DEBUG] parseUriFromRequestFile-regmatch3", funcName, urlFunc)
-					//	fmt.Println("request path:", filePath, "uriFilePath:", uriFilePath)
-					uri := getUriFromUriFile(uriFilePath, urlFunc, true)
-					cloudUri := new(CloudUri)
-					cloudUri.url = uri
-					cloudUri.httpMethod = httpMethod
-					urlSupportsInRequestFile[sdkFileDir+"."+funcName] = *cloudUri
-					urlSupportsInCurrentFile = append(urlSupportsInCurrentFile, funcName)
-				}
-			} else if len(submatch4) > 0 {
-				urlDeclInFunc = true
-				for i := 0; i < len(submatch4); i++ {
-					httpMethod := "get"
-					urlFunc := submatch4[i][1]
-					log.Println("[DEBUG] parseUriFromRequestFile-regmatch4", funcName, urlFunc)
-
-					regUrLDecl := regexp.MustCompile(fmt.Sprintf(`%s\s*:=\s*(\w*)`, urlFunc))
-					urlDeclsMatch := regUrLDecl.FindAllStringSubmatch(funcSrc, 1)
-					if len(urlDeclsMatch) > 0 {
-						urlFunc = urlDeclsMatch[0][1]
-						uri := getUriFromUriFile(uriFilePath, urlFunc, true)
-						cloudUri := new(CloudUri)
-						cloudUri.url = uri
-						cloudUri.httpMethod = httpMethod
-						urlSupportsInRequestFile[sdkFileDir+"."+funcName] = *cloudUri
-						urlSupportsInCurrentFile = append(urlSupportsInCurrentFile, funcName)
-					} else {
-						log.Println("[ERROR] failed find URL decl in request.go", fn.Name.Name, urlFunc)
+	// 通过变量定义的 RequestOpts
+	optsVars := make(map[string][]int)
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if assign, ok := n.(*ast.AssignStmt); ok && len(assign.Lhs) == len(assign.Rhs) {
+			for i, lhs := range assign.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok {
+					if codes := requestOptsOkCodes(assign.Rhs[i]); codes != nil {
+						optsVars[ident.Name] = codes
 					}
-
 				}
 			}
-
-			if !urlDeclInFunc {
-				funcNotDirectUseURLs = append(funcNotDirectUseURLs, fn)
+		}
+		return true
+	})
+
+	start := fn.Pos()
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		for _, arg := range call.Args {
+			codes := requestOptsOkCodes(arg)
+			if ident, ok := arg.(*ast.Ident); ok {
+				codes = optsVars[ident.Name]
+			}
+			if codes != nil {
+				// 匹配的请求从方法名称前的 . 开始
+				rst[int(sel.Sel.Pos()-start)-1] = codes
 			}
-
-			//fmt.Println("fff:", funcSrc)
 		}
+		return true
+	})
+	return rst
+}
 
+// requestOptsOkCodes 返回 &golangsdk.RequestOpts{OkCodes: []int{...}} 中的状态码
+func requestOptsOkCodes(expr ast.Expr) []int {
+	if unary, ok := expr.(*ast.UnaryExpr); ok {
+		expr = unary.X
+	}
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	if sel, ok := lit.Type.(*ast.SelectorExpr); !ok || sel.Sel.Name != "RequestOpts" {
+		return nil
 	}
 
-	//处理第一次没有匹配到的
-	parseRequestFuncNotDirect(set, sdkFileDir, resourceFilebytes, funcNotDirectUseURLs, urlSupportsInCurrentFile)
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if key, ok := kv.Key.(*ast.Ident); !ok || key.Name != "OkCodes" {
+			continue
+		}
+		if codesLit, ok := kv.Value.(*ast.CompositeLit); ok {
+			return statusCodesOfLit(codesLit)
+		}
+	}
+	return nil
 }
 
-func parseRequestFuncNotDirect(set *token.FileSet, sdkFileDir string, resourceFilebytes []byte, funcNotDirectUseURLs []*ast.FuncDecl, urlSupportsInCurrentFile []string) {
-	regStr := fmt.Sprintf(`(%s)\(`, strings.Join(urlSupportsInCurrentFile, "|"))
-
-	reg := regexp.MustCompile(regStr)
-	for _, fn := range funcNotDirectUseURLs {
-		startIndex := set.Position(fn.Pos()).Offset
-		endIndex := set.Position(fn.End()).Offset
-
-		funcSrc := string(resourceFilebytes[startIndex:endIndex])
-		funcName := fn.Name.Name
-		//判断是否有 有间接调用
-		submatch := reg.FindAllStringSubmatch(funcSrc, -1)
-		if len(submatch) > 0 {
-			for i := 0; i < len(submatch); i++ {
-				actualFuncName := submatch[i][1]
-
-				v, ok := urlSupportsInRequestFile[sdkFileDir+"."+actualFuncName]
-				if ok {
-					cloudUri := new(CloudUri)
-					cloudUri.url = v.url
-					cloudUri.httpMethod = v.httpMethod
-					urlSupportsInRequestFile[sdkFileDir+"."+funcName] = *cloudUri
-				}
-
+// statusCodesOfLit 返回 []int{...} 中的状态码
+func statusCodesOfLit(lit *ast.CompositeLit) []int {
+	codes := []int{}
+	for _, c := range lit.Elts {
+		if basic, ok := c.(*ast.BasicLit); ok {
+			if code, err := strconv.Atoi(basic.Value); err == nil {
+				codes = append(codes, code)
 			}
+		}
+	}
+	return codes
+}
 
+// appendCloudUri 添加不重复的请求
+func appendCloudUri(list []CloudUri, uri CloudUri) []CloudUri {
+	for _, v := range list {
+		if v.url == uri.url && v.httpMethod == uri.httpMethod && v.action == uri.action {
+			return list
 		}
 	}
+	return append(list, uri)
 }
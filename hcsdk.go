@@ -6,13 +6,18 @@ import (
 	"go/parser"
 	"go/token"
 	"io/ioutil"
-	"log"
+	"os"
 	"regexp"
 	"strings"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 )
 
 var clientConfig = make(map[string]string)
 
+// key: hc_config.go 中创建client的方法名称, value: 返回的client所属的SDK包
+var clientPackageConfig = make(map[string]string)
+
 func getCategoryFromClientConfig(clientName string) string {
 	v, ok := clientConfig[clientName]
 	if ok {
@@ -29,19 +34,19 @@ func getCategoryFromClientConfig(clientName string) string {
 	return ""
 }
 
-func parseHCConfigFile(filePath string) {
+func parseHCConfigFile(filePath string) error {
 	set := token.NewFileSet()
 	f, err := parser.ParseFile(set, filePath, nil, 0)
 	if err != nil {
-		log.Println("Failed to parse file:", filePath, err)
-		return
+		return err
 	}
 
 	resourceFilebytes, err := ioutil.ReadFile(filePath)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
+	sdkPackages := parseSdkImports(f, hcsdkPrefix)
 	for _, d := range f.Decls {
 		if fn, isFn := d.(*ast.FuncDecl); isFn {
 			startIndex := set.Position(fn.Pos()).Offset
@@ -49,10 +54,17 @@ func parseHCConfigFile(filePath string) {
 			funcSrc := string(resourceFilebytes[startIndex:endIndex])
 			funcName := fn.Name.Name
 
+			// 记录返回的client类型 eg: func (c *Config) HcVpcV3Client(region string) (*vpcv3.VpcClient, error)
+			if fn.Type.Results != nil && len(fn.Type.Results.List) > 0 {
+				if c, ok := hcClientOfType(fn.Type.Results.List[0].Type, sdkPackages); ok {
+					clientPackageConfig[funcName] = c.sdkPackage
+				}
+			}
+
 			reg := regexp.MustCompile(`NewHcClient\(.*, "(.*)"`)
 			submatch := reg.FindAllStringSubmatch(funcSrc, -1)
 			if len(submatch) < 1 {
-				log.Println("skip parse config method:", funcName)
+				logDebug("skip the config method without service client", "func", funcName)
 				continue
 			}
 
@@ -62,49 +74,31 @@ func parseHCConfigFile(filePath string) {
 		}
 	}
 
-	//log.Println("[DEBUG] client config:", clientConfig)
+	return nil
 }
 
 // 解析资源文件的主入口
 func parseResourceFile2(resourceName string, filePath string, file *ast.File, fset *token.FileSet, publicFuncs []string,
-	newResourceName string) (resourceName2 string, description string, allURI []CloudUri, rpath string, newResourceName2 string) {
-
-	// 先找到使用SDK的地方
-	usedPackages := []string{}
-	sdkPackages := make(map[string]string) //key： 先取别名>包名
-	for _, d := range file.Imports {
-		fullPath := strings.Trim(d.Path.Value, `"`)
-		if strings.Contains(fullPath, "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/") {
-			// 忽略 .../model 的包并去重
-			realPath := strings.TrimSuffix(fullPath, "/model")
-			if sliceContains(usedPackages, realPath) {
-				continue
-			}
-
-			alias := realPath[strings.LastIndex(realPath, "/")+1:]
-			if d.Name != nil {
-				alias = d.Name.Name
-			}
-			sdkPackages[alias] = realPath
-			usedPackages = append(usedPackages, realPath)
-		}
-	}
+	newResourceName string) (resourceName2 string, description string, allURI []CloudUri, rpath string, newResourceName2 string,
+	err error) {
 
-	log.Printf("==== importing sdk packages: %#v ====\n", sdkPackages)
+	// 先找到使用SDK的地方, client包和model包分别记录
+	sdkPackages := parseSdkImports(file, hcsdkPrefix)
+	logDebug("importing SDK packages", "packages", fmt.Sprint(sdkPackages))
 
 	resourceFilebytes, err := ioutil.ReadFile(filePath)
 	if err != nil {
-		log.Fatal(err)
+		return resourceName, "", nil, filePath, newResourceName, err
 	}
 
 	allResourceFileFunc := findAllFunc(file, fset)
 
 	allURI = findAllURI2(sdkPackages, resourceFilebytes, allResourceFileFunc, fset, publicFuncs)
 
-	return resourceName, "", allURI, filePath, newResourceName
+	return resourceName, resourceDescription(newResourceName, file), allURI, filePath, newResourceName, nil
 }
 
-func findAllURI2(sdkPackages map[string]string, resourceFileBytes []byte, funcDecls []*ast.FuncDecl, fset *token.FileSet,
+func findAllURI2(sdkPackages []sdkImport, resourceFileBytes []byte, funcDecls []*ast.FuncDecl, fset *token.FileSet,
 	publicFuncs []string) (r []CloudUri) {
 
 	rt := []CloudUri{}
@@ -116,7 +110,7 @@ func findAllURI2(sdkPackages map[string]string, resourceFileBytes []byte, funcDe
 	return removeDuplicateCloudUri(rt)
 }
 
-func findURIFromResourceFunc2(curResourceFuncDecl *ast.FuncDecl, sdkPackages map[string]string,
+func findURIFromResourceFunc2(curResourceFuncDecl *ast.FuncDecl, sdkPackages []sdkImport,
 	resourceFileBytes []byte, funcDecls []*ast.FuncDecl, fset *token.FileSet, publicFuncs []string) []CloudUri {
 
 	startIndex := fset.Position(curResourceFuncDecl.Pos()).Offset
@@ -126,84 +120,354 @@ func findURIFromResourceFunc2(curResourceFuncDecl *ast.FuncDecl, sdkPackages map
 	funcName := curResourceFuncDecl.Name.Name
 	cloudUriArray := []CloudUri{}
 
-	for _, sdkFilePath := range sdkPackages {
-		// 找到所有使用client的方法 eg: response, err := client.AddAlarmRule(&createReq)
-		// 或者 _, err := client.UpdateTask(&model.UpdateTaskRequest{
-		reg := regexp.MustCompile(`= (.*[c|C]lient)\.(\w*)\(.*[)|{]`)
-		allSubMatch := reg.FindAllStringSubmatch(funcSrc, -1)
-		for i := 0; i < len(allSubMatch); i++ {
-			// 0: 全部字符串, 1: client名称, 2: 方法名称
-			clientBeenUsed := allSubMatch[i][1]
-			sdkFunctionName := allSubMatch[i][2]
-			log.Printf("find function %s used %s.%s\n", funcName, clientBeenUsed, sdkFunctionName)
+	// 根据变量的类型确定client所属的SDK包
+	hcClients := parseHcClientsInFunc(curResourceFuncDecl, sdkPackages)
+
+	// 找到所有client的方法调用 eg: response, err := client.AddAlarmRule(&createReq)
+	// 或者 _, err := vpcV3.UpdateTask(&model.UpdateTaskRequest{
+	for _, call := range findMethodCalls(curResourceFuncDecl) {
+		clientBeenUsed := call.receiver
+		sdkFunctionName := call.method
+
+		var sdkFilePaths []string
+		var clientName string
+		// 变量的类型是SDK的client, 没有绑定的client可能是 golangsdk 或者 http 的client, eg: client.Request, httpClient.Do
+		bound := true
+		if call.receiverCall != nil {
+			// 链式调用只处理创建client的调用, 忽略 .WithRetry(...).Invoke() 等
+			c, ok := hcClientOfCall(call.receiverCall, sdkPackages)
+			if !ok {
+				continue
+			}
+			clientBeenUsed = c.clientName
+			sdkFilePaths = []string{c.sdkPackage}
+			clientName = c.clientName
+		} else if c, ok := hcClients.lookup(clientBeenUsed, call.pos); ok {
+			sdkFilePaths = []string{c.sdkPackage}
+			clientName = c.clientName
+		} else if hcClientNameReg.MatchString(clientBeenUsed) {
+			// 无法确定类型的client, 根据请求参数的类型或者所有导入的包查找
+			sdkFilePaths = getHcClientPackages(sdkFunctionName, funcSrc, sdkPackages)
+			bound = false
+		} else {
+			continue
+		}
+		logDebug("found the SDK call", "func", funcName, "sdk_func", sdkFunctionName, "client", clientBeenUsed,
+			"client_type", clientName)
 
+		resolved := false
+		for _, sdkFilePath := range sdkFilePaths {
 			// 1. 根据方法名称找到对应的URI
-			cloudUri := parseUriFromSdk2(sdkFilePath, sdkFunctionName)
-			if cloudUri.url != "" {
+			cloudUris := parseUriFromSdk2(sdkFilePath, sdkFunctionName)
+			if len(cloudUris) > 0 {
+				resolved = true
 				// 2. 根据使用到的client ，向上找最近的一个 Client定义, 并根据它找到 resourceType,version等信息
-				clientName, err := parseClientDecl2(clientBeenUsed, funcSrc, curResourceFuncDecl, resourceFileBytes, funcDecls, fset)
+				resourceType := "unknown"
+				var serviceCatalog config.ServiceCatalog
+				var err error
+				if clientName == "" {
+					clientName, err = parseClientDecl2(clientBeenUsed, funcSrc, curResourceFuncDecl, resourceFileBytes, funcDecls, fset)
+				}
 				if err != nil {
-					log.Println("found none client declares, so skip:", clientBeenUsed, funcName)
-					cloudUri.resourceType = "unknown"
+					logWarn("client declaration not found", "func", funcName, "sdk_func", sdkFunctionName,
+						"client", clientBeenUsed, "reason", err)
 				} else {
 					// 3. 找到client对应的catalog
 					categoryName := getCategoryFromClientConfig(clientName)
-					log.Printf("category of client %s is %s\n", clientName, categoryName)
+					logDebug("found the service category", "client", clientName, "category", categoryName)
 
 					if serviceCategory := parseEndPointByClient(categoryName); serviceCategory != nil {
-						cloudUri.resourceType = serviceCategory.Name
-						cloudUri.serviceCatalog = *serviceCategory
+						resourceType = serviceCategory.Name
+						serviceCatalog = *serviceCategory
 					} else {
-						log.Printf("[ERROR] can not find service catalog of %s\n", categoryName)
+						resourceType = ""
+						logError("service catalog not found", "client", clientName, "category", categoryName)
 					}
 				}
 
-				cloudUriArray = append(cloudUriArray, cloudUri)
+				for _, cloudUri := range cloudUris {
+					cloudUri.resourceType = resourceType
+					cloudUri.serviceCatalog = serviceCatalog
+					cloudUri.sdkFunction = sdkFunctionName
+					cloudUri.terraformFunction = funcName
+					cloudUri.source = sourcePosition(fset, call.pos)
+					cloudUri.polling = callPolling(curResourceFuncDecl, call.pos)
+					// 在循环中调用支持分页的API, 例如根据 marker 查询所有的数据
+					if isInLoop(curResourceFuncDecl, call.pos) {
+						cloudUri.pagination = parametersPagination(cloudUri.parameters)
+					}
+					cloudUriArray = append(cloudUriArray, cloudUri)
+				}
 			} else {
-				log.Println("parseUriFromSdk2 return empty", sdkFunctionName, sdkFilePath)
+				logDebug("the SDK call is not found in the package", "func", funcName, "sdk_func", sdkFunctionName,
+					"sdk_package", sdkFilePath)
 			}
 		}
+
+		if !resolved && !bound {
+			logDebug("skip the call of the client which is not bound to an SDK package", "func", funcName,
+				"sdk_func", sdkFunctionName, "client", clientBeenUsed)
+		} else if !resolved {
+			logWarn("unresolved SDK call", "func", funcName, "sdk_func", sdkFunctionName, "client", clientBeenUsed,
+				"reason", "no URL found in the imported SDK packages")
+			recorder.addUnresolvedCall(unresolvedCall{
+				Func:    funcName,
+				SdkFunc: clientBeenUsed + "." + sdkFunctionName,
+			})
+		}
 	}
 
 	return cloudUriArray
 }
 
-func parseUriFromSdk2(sdkFilePath string, sdkFunctionName string) CloudUri {
-	sdkFileDir := "./vendor/" + sdkFilePath + "/"
+var hcClientNameReg = regexp.MustCompile(`[cC]lient$`)
+
+// hcClient huaweicloud-sdk-go-v3 client变量的类型
+type hcClient struct {
+	clientName string // 创建client的config方法名称或者client的类型名称, 用于查找catalog
+	sdkPackage string // client所属的SDK包
+	pos        token.Pos
+}
+
+// hcClientScope 函数中client变量的定义, 同一个变量可以被多次赋值
+type hcClientScope map[string][]hcClient
+
+// lookup 返回调用位置之前最近一次定义的client类型
+func (scope hcClientScope) lookup(name string, pos token.Pos) (hcClient, bool) {
+	var rst hcClient
+	found := false
+	for _, c := range scope[name] {
+		if c.pos <= pos {
+			rst, found = c, true
+		}
+	}
+	return rst, found
+}
+
+// parseHcClientsInFunc 解析函数参数和变量定义中client的类型
+func parseHcClientsInFunc(fn *ast.FuncDecl, sdkPackages []sdkImport) hcClientScope {
+	scope := make(hcClientScope)
+	add := func(name string, c hcClient, pos token.Pos) {
+		if name == "_" {
+			return
+		}
+		c.pos = pos
+		scope[name] = append(scope[name], c)
+	}
+
+	// 参数 eg: func deleteVpc(client *v3.VpcClient, id string)
+	if fn.Type.Params != nil {
+		for _, field := range fn.Type.Params.List {
+			if c, ok := hcClientOfType(field.Type, sdkPackages); ok {
+				for _, name := range field.Names {
+					add(name.Name, c, fn.Pos())
+				}
+			}
+		}
+	}
+	if fn.Body == nil {
+		return scope
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.AssignStmt:
+			// client, err := cfg.HcVpcV3Client(region) 或者 client := v3.NewVpcClient(hcClient)
+			if len(x.Rhs) != 1 || len(x.Lhs) == 0 {
+				return true
+			}
+			ident, ok := x.Lhs[0].(*ast.Ident)
+			if !ok {
+				return true
+			}
+			if c, ok := hcClientOfCall(x.Rhs[0], sdkPackages); ok {
+				add(ident.Name, c, x.Pos())
+			}
+		case *ast.ValueSpec:
+			// var client *v3.VpcClient 或者 var client = v3.NewVpcClient(hcClient)
+			if c, ok := hcClientOfType(x.Type, sdkPackages); ok {
+				for _, name := range x.Names {
+					add(name.Name, c, x.Pos())
+				}
+			} else if len(x.Values) == 1 {
+				if c, ok := hcClientOfCall(x.Values[0], sdkPackages); ok {
+					add(x.Names[0].Name, c, x.Pos())
+				}
+			}
+		}
+		return true
+	})
+	return scope
+}
+
+// hcClientOfType 解析类型 *v3.VpcClient
+func hcClientOfType(expr ast.Expr, sdkPackages []sdkImport) (hcClient, bool) {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || !strings.HasSuffix(sel.Sel.Name, "Client") {
+		return hcClient{}, false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return hcClient{}, false
+	}
+
+	for _, imp := range sdkPackages {
+		if imp.localName == pkg.Name && !imp.isModel() {
+			return hcClient{clientName: sel.Sel.Name, sdkPackage: imp.path}, true
+		}
+	}
+	return hcClient{}, false
+}
+
+// hcClientOfCall 解析创建client的调用, 包括 hc_config.go 中的方法和SDK中的 NewXxxClient
+func hcClientOfCall(expr ast.Expr, sdkPackages []sdkImport) (hcClient, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return hcClient{}, false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return hcClient{}, false
+	}
+
+	funcName := sel.Sel.Name
+	if sdkPackage, ok := clientPackageConfig[funcName]; ok {
+		return hcClient{clientName: funcName, sdkPackage: sdkPackage}, true
+	}
+
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok || !strings.HasPrefix(funcName, "New") || !strings.HasSuffix(funcName, "Client") {
+		return hcClient{}, false
+	}
+	for _, imp := range sdkPackages {
+		if imp.localName == pkg.Name && !imp.isModel() {
+			return hcClient{clientName: strings.TrimPrefix(funcName, "New"), sdkPackage: imp.path}, true
+		}
+	}
+	return hcClient{}, false
+}
+
+// methodCall 函数中 receiver.Method(...) 形式的调用
+type methodCall struct {
+	receiver     string
+	receiverCall ast.Expr // 链式调用中返回receiver的调用 eg: v3.NewVpcClient(hcClient).ShowVpc(request)
+	method       string
+	pos          token.Pos
+}
+
+// findMethodCalls 按照源码顺序找到函数中所有 receiver.Method(...) 形式的调用
+func findMethodCalls(fn *ast.FuncDecl) []methodCall {
+	calls := []methodCall{}
+	if fn.Body == nil {
+		return calls
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		switch recv := sel.X.(type) {
+		case *ast.Ident:
+			calls = append(calls, methodCall{receiver: recv.Name, method: sel.Sel.Name, pos: call.Pos()})
+		case *ast.CallExpr:
+			calls = append(calls, methodCall{receiverCall: recv, method: sel.Sel.Name, pos: call.Pos()})
+		}
+		return true
+	})
+	return calls
+}
+
+// getHcClientPackages 根据请求参数的类型 model.XxxRequest 确定调用所属的SDK包,
+// 无法确定时返回所有导入的client包
+func getHcClientPackages(sdkFunctionName string, funcSrc string, sdkPackages []sdkImport) []string {
+	for _, imp := range sdkPackages {
+		if !imp.isModel() {
+			continue
+		}
+		reg := regexp.MustCompile(fmt.Sprintf(`(?:^|[^\w.])%s\.%sRequest\b`, regexp.QuoteMeta(imp.localName),
+			regexp.QuoteMeta(sdkFunctionName)))
+		if reg.MatchString(funcSrc) {
+			logDebug("bind the SDK call by the request type", "sdk_func", sdkFunctionName, "sdk_package", imp.clientPath())
+			return []string{imp.clientPath()}
+		}
+	}
 
-	cUri := getUriFromRequestFile2(sdkFileDir, sdkFunctionName, true)
-	fmt.Println("mmmm", cUri.url, cUri.httpMethod, sdkFunctionName, sdkFileDir)
+	rst := []string{}
+	for _, imp := range sdkPackages {
+		if !sliceContains(rst, imp.clientPath()) {
+			rst = append(rst, imp.clientPath())
+		}
+	}
+	return rst
+}
 
-	return CloudUri{
-		url:         cUri.url,
-		httpMethod:  cUri.httpMethod,
-		operationId: sdkFunctionName,
-		filePath:    sdkFilePath,
+func parseUriFromSdk2(sdkFilePath string, sdkFunctionName string) []CloudUri {
+	sdkFileDir := basePath + "vendor/" + sdkFilePath + "/"
+
+	rst := []CloudUri{}
+	for _, cUri := range getUriFromRequestFile2(sdkFileDir, sdkFunctionName, true) {
+		logDebug("resolved the SDK call", "sdk_func", sdkFunctionName, "sdk_package", sdkFilePath,
+			"method", cUri.httpMethod, "url", cUri.url)
+
+		rst = append(rst, CloudUri{
+			url:          cUri.url,
+			httpMethod:   cUri.httpMethod,
+			operationId:  qualifiedOperationId(sdkFilePath, strings.TrimSuffix(sdkFunctionName, invokerSuffix)),
+			summary:      cUri.summary,
+			filePath:     sdkFilePath,
+			consumes:     cUri.consumes,
+			parameters:   cUri.parameters,
+			requestBody:  cUri.requestBody,
+			responseBody: cUri.responseBody,
+			statusCodes:  cUri.statusCodes,
+			action:       cUri.action,
+		})
 	}
+	return qualifyOperations(rst)
 }
 
-func getUriFromRequestFile2(sdkFileDir string, funcName string, firstTime bool) CloudUri {
+// client.ShowVpcInvoker(request).WithRetry(...).Invoke() 形式的调用
+const invokerSuffix = "Invoker"
+
+func getUriFromRequestFile2(sdkFileDir string, funcName string, firstTime bool) []CloudUri {
 	v, ok := urlSupportsInRequestFile[sdkFileDir+"."+funcName]
 	if ok {
 		return v
 	}
 
 	if firstTime {
-		parseUriFromRequestFile2(sdkFileDir)
+		if err := parseUriFromRequestFile2(sdkFileDir); err != nil {
+			reportScanError(stageSDK, sdkFileDir, err)
+		}
 		return getUriFromRequestFile2(sdkFileDir, funcName, false)
 	}
 
-	log.Printf("[ERROR] can not find URL of %s in %s\n", funcName, sdkFileDir)
-	return CloudUri{}
+	// XxxInvoker 和 Xxx 使用相同的请求定义
+	if strings.HasSuffix(funcName, invokerSuffix) {
+		if v, ok := urlSupportsInRequestFile[sdkFileDir+"."+strings.TrimSuffix(funcName, invokerSuffix)]; ok {
+			logDebug("resolved the invoker by the request method", "sdk_func", funcName, "sdk_package", sdkFileDir)
+			return v
+		}
+	}
+
+	logDebug("can not find the URL", "sdk_func", funcName, "sdk_package", sdkFileDir)
+	return nil
 }
 
-func getClientAndMetaFile(sdkDir string) (string, string) {
+func getClientAndMetaFile(sdkDir string) (string, string, error) {
 	var clientFile, metaFile string
 
 	dir, err := ioutil.ReadDir(sdkDir)
 	if err != nil {
-		log.Printf("faild to read %s: %s\n", sdkDir, err)
-		return "", ""
+		return "", "", err
 	}
 
 	for _, fi := range dir {
@@ -218,31 +482,167 @@ func getClientAndMetaFile(sdkDir string) (string, string) {
 			metaFile = sdkDir + "/" + name
 		}
 	}
-	return clientFile, metaFile
+	return clientFile, metaFile, nil
 }
 
 type HttpRequest struct {
-	Method string
-	URI    string
+	Method       string
+	URI          string
+	ContentType  string
+	Parameters   []apiParameter
+	RequestBody  *jsonSchema
+	ResponseBody *jsonSchema
 }
 
-func parseUriFromRequestFile2(sdkFileDir string) {
-	clientPath, metaPath := getClientAndMetaFile(sdkFileDir)
+// action 返回 .../action 请求的请求体根节点, eg: BatchStopServers 的 os-stop
+func (r HttpRequest) action() string {
+	if !isActionUrl(r.URI) {
+		return ""
+	}
+	return bodyActionKey(r.RequestBody)
+}
+
+// key: SDK包的目录, value: model包中定义的结构体
+var hcModelStructs = make(map[string]map[string]*ast.StructType)
+
+// getHcModelStructs 解析SDK包中 model 目录下的所有结构体
+func getHcModelStructs(sdkFileDir string) map[string]*ast.StructType {
+	if v, ok := hcModelStructs[sdkFileDir]; ok {
+		return v
+	}
+
+	structs := make(map[string]*ast.StructType)
+	hcModelStructs[sdkFileDir] = structs
+
+	modelDir := strings.TrimSuffix(sdkFileDir, "/") + hcModelSuffix
+	pkgs, err := parser.ParseDir(token.NewFileSet(), modelDir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		logWarn("failed to parse the model package", "sdk_package", sdkFileDir, "reason", err)
+		return structs
+	}
+
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			for _, d := range f.Decls {
+				gen, ok := d.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					ts := spec.(*ast.TypeSpec)
+					if st, ok := ts.Type.(*ast.StructType); ok {
+						structs[ts.Name.Name] = st
+					}
+				}
+			}
+		}
+	}
+	return structs
+}
+
+// 参数位置 def.Path 等对应swagger中的 in
+var hcLocationTypes = map[string]string{
+	"Path":   "path",
+	"Query":  "query",
+	"Header": "header",
+	"Form":   "formData",
+}
+
+// hcFieldDef GenReqDefForXxx 中通过 WithRequestField 或者 WithResponseField 定义的字段
+type hcFieldDef struct {
+	name     string // 结构体中的字段名称
+	jsonTag  string
+	location string // Path, Query, Header, Form, Body 等
+}
+
+// parseHcFieldDefs 解析 GenReqDefForXxx 中的字段定义, marker 为 "WithRequestField(" 或者 "WithResponseField("
+func parseHcFieldDefs(funcSrc string, marker string) []hcFieldDef {
+	regName := regexp.MustCompile(`WithName\("(\w*)"\)`)
+	regJsonTag := regexp.MustCompile(`WithJsonTag\("([^"]*)"\)`)
+	regLocation := regexp.MustCompile(`WithLocationType\(def\.(\w*)\)`)
+
+	defs := []hcFieldDef{}
+	for _, fieldSrc := range strings.Split(funcSrc, marker)[1:] {
+		nameMatch := regName.FindStringSubmatch(fieldSrc)
+		locationMatch := regLocation.FindStringSubmatch(fieldSrc)
+		if len(nameMatch) < 2 || len(locationMatch) < 2 {
+			continue
+		}
+
+		fieldDef := hcFieldDef{name: nameMatch[1], location: locationMatch[1]}
+		if jsonTagMatch := regJsonTag.FindStringSubmatch(fieldSrc); len(jsonTagMatch) > 1 {
+			fieldDef.jsonTag = jsonTagMatch[1]
+		}
+		defs = append(defs, fieldDef)
+	}
+	return defs
+}
+
+// parseHcRequestFields 解析 GenReqDefForXxx 中通过 WithRequestField 定义的参数, 类型和是否必选从 XxxRequest 中获取
+// eg: reqDefBuilder.WithRequestField(def.NewFieldDef().WithName("Limit").WithJsonTag("limit").WithLocationType(def.Query))
+func parseHcRequestFields(funcSrc string, request *ast.StructType) []apiParameter {
+	params := []apiParameter{}
+	for _, fieldDef := range parseHcFieldDefs(funcSrc, "WithRequestField(") {
+		// 请求体在 requestBody 中描述
+		in, ok := hcLocationTypes[fieldDef.location]
+		if !ok {
+			continue
+		}
+
+		param := apiParameter{name: fieldDef.name, in: in, typ: "string"}
+		if fieldDef.jsonTag != "" {
+			param.name = fieldDef.jsonTag
+		}
+
+		if field := findStructField(request, fieldDef.name); field != nil {
+			param.typ, param.itemsType = swaggerType(field.Type)
+			// 必选参数不是指针, 也没有 omitempty
+			_, isPointer := field.Type.(*ast.StarExpr)
+			param.required = !isPointer && (field.Tag == nil || !strings.Contains(field.Tag.Value, "omitempty"))
+		}
+		if param.in == "path" {
+			param.required = true
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+// findStructField 根据字段名称查找结构体中的字段
+func findStructField(st *ast.StructType, name string) *ast.Field {
+	if st == nil {
+		return nil
+	}
+	for _, field := range st.Fields.List {
+		for _, n := range field.Names {
+			if n.Name == name {
+				return field
+			}
+		}
+	}
+	return nil
+}
+
+func parseUriFromRequestFile2(sdkFileDir string) error {
+	clientPath, metaPath, err := getClientAndMetaFile(sdkFileDir)
+	if err != nil {
+		return err
+	}
 	if clientPath == "" || metaPath == "" {
-		log.Println("[ERROR] cant find the requests files in ", sdkFileDir)
-		return
+		return fmt.Errorf("can not find the client or meta file in %s", sdkFileDir)
 	}
 
 	metaSet := token.NewFileSet()
 	f1, err := parser.ParseFile(metaSet, metaPath, nil, 0)
 	if err != nil {
-		log.Println("Failed to parse file:", metaPath, err)
-		return
+		return err
 	}
 
 	filebytes, err := ioutil.ReadFile(metaPath)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	var metaAPIs = make(map[string]*HttpRequest)
@@ -263,6 +663,7 @@ func parseUriFromRequestFile2(sdkFileDir string) {
 
 		reg1 := regexp.MustCompile(`WithMethod\(http.Method(\w*)\)`)
 		reg2 := regexp.MustCompile(`WithPath\("(.*)"\)`)
+		reg3 := regexp.MustCompile(`WithContentType\("(.*)"\)`)
 
 		submatch1 := reg1.FindStringSubmatch(funcSrc)
 		submatch2 := reg2.FindStringSubmatch(funcSrc)
@@ -271,23 +672,33 @@ func parseUriFromRequestFile2(sdkFileDir string) {
 			continue
 		}
 
-		metaAPIs[funcName] = &HttpRequest{
+		requestInfo := &HttpRequest{
 			Method: submatch1[1],
 			URI:    submatch2[1],
 		}
+		if submatch3 := reg3.FindStringSubmatch(funcSrc); len(submatch3) > 1 {
+			requestInfo.ContentType = submatch3[1]
+		}
+		requestType := strings.TrimPrefix(funcName, "GenReqDefFor") + "Request"
+		request := getHcModelStructs(sdkFileDir)[requestType]
+		requestInfo.Parameters = parseHcRequestFields(funcSrc, request)
+		if strings.Contains(funcSrc, "WithLocationType(def.Body)") {
+			requestInfo.RequestBody = hcRequestBodySchema(sdkFileDir, request)
+		}
+		requestInfo.ResponseBody = hcResponseBodySchema(sdkFileDir, funcSrc)
+		metaAPIs[funcName] = requestInfo
 	}
-	log.Printf("API mapping in %s: %#v\n", sdkFileDir, metaAPIs)
+	logDebug("parsed the meta file", "sdk_package", sdkFileDir, "apis", len(metaAPIs))
 
 	clientSet := token.NewFileSet()
-	f2, err := parser.ParseFile(clientSet, clientPath, nil, 0)
+	f2, err := parser.ParseFile(clientSet, clientPath, nil, parser.ParseComments)
 	if err != nil {
-		log.Println("Failed to parse file:", clientPath, err)
-		return
+		return err
 	}
 
 	resourceFilebytes, err := ioutil.ReadFile(clientPath)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	for _, d := range f2.Decls {
@@ -301,7 +712,9 @@ func parseUriFromRequestFile2(sdkFileDir string) {
 		endIndex := clientSet.Position(fn.End()).Offset
 		funcSrc := string(resourceFilebytes[startIndex:endIndex])
 
-		reg := regexp.MustCompile(`:= (GenReqDefFor\w*)\(\)`)
+		// requestDef := GenReqDefForShowVpc()
+		// 或者 return &ShowVpcInvoker{invoker.NewBaseInvoker(c.HcClient, request, GenReqDefForShowVpc())}
+		reg := regexp.MustCompile(`\b(GenReqDefFor\w*)\(\)`)
 		submatch := reg.FindStringSubmatch(funcSrc)
 
 		if len(submatch) < 2 {
@@ -310,17 +723,26 @@ func parseUriFromRequestFile2(sdkFileDir string) {
 		metaFunc := submatch[1]
 		requestInfo, ok := metaAPIs[metaFunc]
 		if !ok || requestInfo == nil {
-			log.Printf("%s is used by %s, but not defined in meta file", metaFunc, funcName)
+			logWarn("the request definition is not found in meta file", "sdk_func", funcName, "meta_func", metaFunc,
+				"sdk_package", sdkFileDir)
 			continue
 		}
 
-		urlSupportsInRequestFile[sdkFileDir+"."+funcName] = CloudUri{
-			url:        requestInfo.URI,
-			httpMethod: strings.ToLower(requestInfo.Method),
+		urlSupportsInRequestFile[sdkFileDir+"."+funcName] = []CloudUri{
+			{
+				url:          requestInfo.URI,
+				httpMethod:   strings.ToLower(requestInfo.Method),
+				summary:      docSummary(fn.Doc, funcName),
+				consumes:     requestInfo.ContentType,
+				parameters:   requestInfo.Parameters,
+				requestBody:  requestInfo.RequestBody,
+				responseBody: requestInfo.ResponseBody,
+				action:       requestInfo.action(),
+			},
 		}
 	}
 
-	return
+	return nil
 }
 
 func parseClientDecl2(client string, funcSrc string, curResourceFuncDecl *ast.FuncDecl, resourceFileBytes []byte,
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetUriFromRequestFile2(t *testing.T) {
+	defer setupFixtureScan(t)()
+
+	sdkDir := basePath + "vendor/" + hcsdkPrefix + "vpc/v3/"
+	cases := map[string][]string{
+		"ShowVpc": {"get /v3/{project_id}/vpc/vpcs/{vpc_id}"},
+		// 在 NewBaseInvoker 中引用请求定义
+		"ListVpcsInvoker": {"get /v3/{project_id}/vpc/vpcs"},
+		// 没有定义的 Invoker 使用同名方法的请求定义
+		"ShowVpcInvoker": {"get /v3/{project_id}/vpc/vpcs/{vpc_id}"},
+		"NotExist":       {},
+	}
+
+	for funcName, want := range cases {
+		got := []string{}
+		for _, uri := range getUriFromRequestFile2(sdkDir, funcName, true) {
+			got = append(got, uri.httpMethod+" "+uri.url)
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("getUriFromRequestFile2(%s) = %v, want %v", funcName, got, want)
+		}
+	}
+}
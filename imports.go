@@ -0,0 +1,89 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"strings"
+)
+
+const (
+	golangsdkPrefix = "github.com/chnsz/golangsdk/openstack/"
+	hcsdkPrefix     = "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/"
+	hcModelSuffix   = "/model"
+)
+
+// key: 包的导入路径, value: vendor中定义的包名
+var sdkPackageNames = make(map[string]string)
+
+// sdkImport 资源文件中导入的一个SDK包
+type sdkImport struct {
+	localName string // 文件中引用该包使用的名称: 别名或者包名
+	path      string // 导入路径
+}
+
+// isModel 判断是否是 huaweicloud-sdk-go-v3 的 model 包
+func (imp sdkImport) isModel() bool {
+	return strings.HasSuffix(imp.path, hcModelSuffix)
+}
+
+// clientPath 返回定义client的包, model 包对应它的上一级目录
+func (imp sdkImport) clientPath() string {
+	return strings.TrimSuffix(imp.path, hcModelSuffix)
+}
+
+// parseSdkImports 解析资源文件中导入的SDK包, 每个导入单独记录, 没有别名时使用vendor中的真实包名
+func parseSdkImports(file *ast.File, prefix string) []sdkImport {
+	imports := []sdkImport{}
+	for _, d := range file.Imports {
+		fullPath := strings.Trim(d.Path.Value, `"`)
+		if !strings.HasPrefix(fullPath, prefix) {
+			continue
+		}
+
+		localName := getSdkPackageName(fullPath)
+		if d.Name != nil {
+			localName = d.Name.Name
+		}
+		// 忽略 _ 和 . 导入, 无法通过名称匹配调用
+		if localName == "_" || localName == "." {
+			logDebug("skip the SDK import without name", "sdk_package", fullPath, "name", localName)
+			continue
+		}
+
+		imports = append(imports, sdkImport{localName: localName, path: fullPath})
+	}
+	return imports
+}
+
+// getSdkPackageName 读取vendor中SDK包的 package 声明, 找不到时使用导入路径的最后一段
+func getSdkPackageName(importPath string) string {
+	if name, ok := sdkPackageNames[importPath]; ok {
+		return name
+	}
+
+	name := importPath[strings.LastIndex(importPath, "/")+1:]
+	sdkDir := basePath + "vendor/" + importPath + "/"
+	if dir, err := ioutil.ReadDir(sdkDir); err == nil {
+		for _, fi := range dir {
+			fileName := fi.Name()
+			if fi.IsDir() || !strings.HasSuffix(fileName, ".go") || strings.HasSuffix(fileName, "_test.go") {
+				continue
+			}
+
+			f, err := parser.ParseFile(token.NewFileSet(), sdkDir+fileName, nil, parser.PackageClauseOnly)
+			if err != nil {
+				logDebug("failed to parse the package clause", "file", sdkDir+fileName, "reason", err)
+				continue
+			}
+			name = f.Name.Name
+			break
+		}
+	} else {
+		logDebug("the SDK package is not found in vendor", "sdk_package", importPath, "reason", err)
+	}
+
+	sdkPackageNames[importPath] = name
+	return name
+}
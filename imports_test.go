@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestParseSdkImports(t *testing.T) {
+	defer setupFixtureScan(t)()
+
+	filePath := basePath + "huaweicloud/services/vpc/resource_huaweicloud_vpc.go"
+	file, err := parser.ParseFile(token.NewFileSet(), filePath, nil, parser.ImportsOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 同名的 model 包分别记录, 没有别名时使用vendor中的包名
+	imports := parseSdkImports(file, hcsdkPrefix)
+	got := []string{}
+	for _, imp := range imports {
+		got = append(got, imp.localName+"="+strings.TrimPrefix(imp.path, hcsdkPrefix))
+	}
+	want := []string{"v2model=vpc/v2/model", "v3=vpc/v3", "model=vpc/v3/model"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected imports:\n got: %v\nwant: %v", got, want)
+	}
+
+	cases := []struct {
+		sdkFunc string
+		funcSrc string
+		want    []string
+	}{
+		{"ShowVpc", "client.ShowVpc(&model.ShowVpcRequest{VpcId: id})", []string{"vpc/v3"}},
+		{"ShowVpcTags", "v2Client.ShowVpcTags(&v2model.ShowVpcTagsRequest{VpcId: id})", []string{"vpc/v2"}},
+		// 请求类型无法确定时, 在所有导入的包中查找
+		{"ShowVpc", "client.ShowVpc(request)", []string{"vpc/v2", "vpc/v3"}},
+	}
+	for _, c := range cases {
+		got := []string{}
+		for _, p := range getHcClientPackages(c.sdkFunc, c.funcSrc, imports) {
+			got = append(got, strings.TrimPrefix(p, hcsdkPrefix))
+		}
+		if fmt.Sprint(got) != fmt.Sprint(c.want) {
+			t.Errorf("getHcClientPackages(%s, %q) = %v, want %v", c.sdkFunc, c.funcSrc, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 日志级别
+const (
+	levelDebug = iota
+	levelInfo
+	levelWarn
+	levelError
+)
+
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+var (
+	// 命令行参数
+	logLevel  string
+	logFormat string
+	logQuiet  bool
+
+	logger = &scanLogger{out: os.Stderr, level: levelInfo}
+)
+
+func init() {
+	flag.StringVar(&logLevel, "log-level", "info", "the minimum log level: debug, info, warn or error")
+	flag.StringVar(&logFormat, "log-format", "text", "the log format: text or json")
+	flag.BoolVar(&logQuiet, "quiet", false, "only print the errors, the same as -log-level=error")
+}
+
+// scanLogger 输出到stderr的结构化日志, 每条日志由级别、消息和若干键值对组成。
+// 常用的键: resource, file, output_file, sdk_func, sdk_package, client, url, method, reason
+// 扫描资源文件时, resource 和 file 会自动添加到日志中, file 始终是扫描的源码文件;
+// 写入的API描述文件和报告使用 output_file。
+type scanLogger struct {
+	mu       sync.Mutex
+	out      io.Writer
+	level    int
+	json     bool
+	resource string
+	file     string
+}
+
+// setupLogger 根据命令行参数设置日志级别和格式
+func setupLogger() error {
+	level := -1
+	for i, name := range levelNames {
+		if strings.EqualFold(logLevel, name) {
+			level = i
+		}
+	}
+	if level < 0 {
+		return fmt.Errorf("invalid log level %q", logLevel)
+	}
+	if logQuiet {
+		level = levelError
+	}
+
+	switch logFormat {
+	case "text":
+		logger.json = false
+	case "json":
+		logger.json = true
+	default:
+		return fmt.Errorf("invalid log format %q", logFormat)
+	}
+
+	logger.level = level
+	return nil
+}
+
+// setLogResource 设置当前扫描的资源, 传入空字符串时清除
+func setLogResource(resource, file string) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+
+	logger.resource = resource
+	logger.file = file
+}
+
+// currentLogResource 返回当前扫描的资源和文件
+func currentLogResource() (string, string) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+
+	return logger.resource, logger.file
+}
+
+func logDebug(msg string, kvs ...interface{}) {
+	logger.log(levelDebug, msg, kvs)
+}
+
+func logInfo(msg string, kvs ...interface{}) {
+	logger.log(levelInfo, msg, kvs)
+}
+
+func logWarn(msg string, kvs ...interface{}) {
+	logger.log(levelWarn, msg, kvs)
+}
+
+func logError(msg string, kvs ...interface{}) {
+	logger.log(levelError, msg, kvs)
+}
+
+func (l *scanLogger) log(level int, msg string, kvs []interface{}) {
+	if level < l.level {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	fields := l.fields(kvs)
+	var line string
+	if l.json {
+		line = formatJSONLog(level, msg, fields)
+	} else {
+		line = formatTextLog(level, msg, fields)
+	}
+	fmt.Fprintln(l.out, line)
+}
+
+type logField struct {
+	key   string
+	value interface{}
+}
+
+// fields 将键值对转换为有序的字段, 并追加当前资源的信息
+func (l *scanLogger) fields(kvs []interface{}) []logField {
+	rst := make([]logField, 0, len(kvs)/2+2)
+	keys := make(map[string]bool)
+	for i := 0; i < len(kvs); i += 2 {
+		key := fmt.Sprint(kvs[i])
+		var value interface{} = "(MISSING)"
+		if i+1 < len(kvs) {
+			value = kvs[i+1]
+		}
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+
+		rst = append(rst, logField{key: key, value: value})
+		keys[key] = true
+	}
+
+	if l.resource != "" && !keys["resource"] {
+		rst = append(rst, logField{key: "resource", value: l.resource})
+	}
+	if l.file != "" && !keys["file"] {
+		rst = append(rst, logField{key: "file", value: l.file})
+	}
+	return rst
+}
+
+func formatTextLog(level int, msg string, fields []logField) string {
+	var b strings.Builder
+	b.WriteString(time.Now().Format(time.RFC3339))
+	b.WriteString(" ")
+	b.WriteString(fmt.Sprintf("%-5s", strings.ToUpper(levelNames[level])))
+	b.WriteString(" ")
+	b.WriteString(msg)
+
+	for _, f := range fields {
+		value := fmt.Sprint(f.value)
+		if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+			value = strconv.Quote(value)
+		}
+		b.WriteString(" ")
+		b.WriteString(f.key)
+		b.WriteString("=")
+		b.WriteString(value)
+	}
+	return b.String()
+}
+
+func formatJSONLog(level int, msg string, fields []logField) string {
+	var b strings.Builder
+	b.WriteString(`{"time":`)
+	writeJSONValue(&b, time.Now().Format(time.RFC3339))
+	b.WriteString(`,"level":`)
+	writeJSONValue(&b, levelNames[level])
+	b.WriteString(`,"msg":`)
+	writeJSONValue(&b, msg)
+
+	for _, f := range fields {
+		b.WriteString(",")
+		writeJSONValue(&b, f.key)
+		b.WriteString(":")
+		writeJSONValue(&b, f.value)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func writeJSONValue(b *strings.Builder, v interface{}) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		raw, _ = json.Marshal(fmt.Sprint(v))
+	}
+	b.Write(raw)
+}
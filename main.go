@@ -8,10 +8,10 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
-	"log"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/jmespath/go-jmespath"
@@ -27,13 +27,10 @@ var (
 	provider           string
 
 	// 保存 openstack/instances.{func} : uri
-	urlSupportsInUriFile     = make(map[string]string)
-	urlSupportsInRequestFile = make(map[string]CloudUri)
+	urlSupportsInRequestFile = make(map[string][]CloudUri)
 )
 
 func init() {
-	log.SetOutput(os.Stdout)
-
 	flag.StringVar(&basePath, "basePath", "./", "base Path")
 	flag.StringVar(&outputDir, "outputDir", "./api/", "api yaml file output Dir")
 	flag.StringVar(&version, "version", "", "provider version")
@@ -45,26 +42,87 @@ func init() {
 func main() {
 
 	flag.Parse()
-	log.Printf("basePath: %s\n", basePath)
+	if err := setupLogger(); err != nil {
+		logError("invalid log options", "reason", err)
+		os.Exit(-1)
+	}
+	logInfo("start scanning", "base_path", basePath)
 
 	// 解析 config, 获取client和catalog的对应关系
-	parseConfigFile(basePath + "huaweicloud/config/config.go")
-	parseHCConfigFile(basePath + "huaweicloud/config/hc_config.go")
+	configFile := basePath + "huaweicloud/config/config.go"
+	if err := parseConfigFile(configFile); err != nil {
+		reportScanError(stageConfig, configFile, err)
+	}
+	hcConfigFile := basePath + "huaweicloud/config/hc_config.go"
+	if err := parseHCConfigFile(hcConfigFile); err != nil {
+		reportScanError(stageConfig, hcConfigFile, err)
+	}
+	// 没有规则时不标记敏感API
+	if err := loadRiskRules(riskRulesPath); err != nil {
+		reportScanError(stageConfig, riskRulesPath, err)
+	}
 
 	// 解析 schema, 获取所有的resource和data source列表
 	rsNames, dsNames, err := parseSchemaInfo(providerSchemaPath, provider)
 	if err != nil {
-		fmt.Printf("Failed to parse %s schema file: %s\n", provider, err)
+		logError("failed to parse the provider schema", "file", providerSchemaPath, "reason", err)
 		os.Exit(-1)
 	}
 
-	// 处理目录和子目录
+	if err := scanProvider(rsNames, dsNames); err != nil {
+		reportScanError(stagePackage, basePath+provider, err)
+	}
+
+	// 将固定的文件替换到指定目录并替换版本号
+	if err := copyStaticFile(outputDir, version); err != nil {
+		reportScanError(stageOutput, outputDir, err)
+	}
+
+	copyTargets := []string{"resource_huaweicloud_gaussdb_mongo_instance.yaml", "resource_huaweicloud_gaussdb_influx_instance.yaml"}
+	for _, target := range copyTargets {
+		if err := copyFromFile(outputDir, "resource_huaweicloud_gaussdb_cassandra_instance.yaml", target); err != nil {
+			reportScanError(stageOutput, target, err)
+		}
+	}
+
+	// 生成统计报告并检查质量门禁
+	report := recorder.buildReport(version)
+	passed := checkQualityGates(report)
+
+	if err := writeCoverageReport(outputDir, report); err != nil {
+		logError("failed to write the coverage report", "output_file", filepath.Join(outputDir, coverageReportFile),
+			"reason", err)
+	}
+	if err := writeDependencyReport(outputDir, buildDependencyReport(report)); err != nil {
+		logError("failed to write the dependency report", "output_file", filepath.Join(outputDir, dependencyReportFile),
+			"reason", err)
+	}
+
+	logGateResults(report)
+	if !passed {
+		logError("the quality gates are not passed", "output_file", filepath.Join(outputDir, coverageReportFile))
+		os.Exit(gateFailedExitCode)
+	}
+}
+
+// scanProvider 处理provider的目录和子目录, 并将API描述文件写入 outputDir
+// 单个目录或者文件的错误会被记录在报告中, 不会中断扫描
+func scanProvider(rsNames, dsNames []string) error {
 	var publicFuncArray []string
 	subPackagePath := basePath + provider + "/"
-	err = filepath.Walk(subPackagePath, func(path string, fInfo os.FileInfo, err error) error {
+	if _, err := os.Stat(subPackagePath); err != nil {
+		return err
+	}
+
+	// 跳过的文件按目录追加写入, 先删除上次扫描的结果
+	if err := os.Remove(outputDir + "skip_files.txt"); err != nil && !os.IsNotExist(err) {
+		reportScanError(stageOutput, outputDir+"skip_files.txt", err)
+	}
+
+	return filepath.Walk(subPackagePath, func(path string, fInfo os.FileInfo, err error) error {
 		if err != nil {
-			log.Printf("scan path %s failed: %s\n", path, err)
-			return err
+			reportScanError(stagePackage, path, err)
+			return nil
 		}
 
 		if fInfo.IsDir() && !isSkipDirectory(path) {
@@ -73,24 +131,11 @@ func main() {
 
 		return nil
 	})
-
-	if err != nil {
-		fmt.Printf("ERROR: scan path failed: %s\n", err)
-	}
-
-	// 将固定的文件替换到指定目录并替换版本号
-	if err := copyStaticFile(outputDir, version); err != nil {
-		fmt.Printf("ERROR: copy static files failed: %s\n", err)
-	}
-
-	copyFromFile(outputDir, "resource_huaweicloud_gaussdb_cassandra_instance.yaml", "resource_huaweicloud_gaussdb_mongo_instance.yaml")
-	copyFromFile(outputDir, "resource_huaweicloud_gaussdb_cassandra_instance.yaml", "resource_huaweicloud_gaussdb_influx_instance.yaml")
 }
 
 func copyStaticFile(outputDir, version string) error {
 	return filepath.Walk("../../config/static/", func(path string, fInfo os.FileInfo, err error) error {
 		if err != nil {
-			log.Printf("scan path %s failed: %s\n", path, err)
 			return err
 		}
 
@@ -101,24 +146,22 @@ func copyStaticFile(outputDir, version string) error {
 
 		rawBytes, err := os.ReadFile(path)
 		if err != nil {
-			fmt.Println(err)
 			return err
 		}
 
 		input := bytes.Replace(rawBytes, []byte("v1.xx.y"), []byte(version), 1)
 
-		fmt.Printf("copy file %s into %s\n", path, outputDir)
+		logInfo("copy the static file", "file", path, "output_dir", outputDir)
 		outputFile := outputDir + fInfo.Name()
 		return os.WriteFile(outputFile, input, 0644)
 	})
 }
 
 func copyFromFile(dir, source, target string) error {
-	fmt.Printf("copy file %s as %s\n", source, target)
+	logInfo("copy the API file", "file", source, "target", target)
 	sourcePath := filepath.Join(dir, source)
 	rawBytes, err := os.ReadFile(sourcePath)
 	if err != nil {
-		fmt.Println(err)
 		return err
 	}
 
@@ -134,43 +177,63 @@ func searchPackage(subPackage string, publicFuncs, rsNames, dsNames []string, pr
 	set := token.NewFileSet()
 	packs, err := parser.ParseDir(set, subPackage, nil, 0)
 	if err != nil {
-		fmt.Printf("Failed to parse package %s: %s\n", subPackage, err)
-		os.Exit(1)
+		// 语法错误的文件会被忽略, 继续处理其他文件
+		reportScanError(stagePackage, subPackage, err)
 	}
 
-	log.Printf("current scan path: %s, package count: %d\n", subPackage, len(packs))
+	logDebug("scan the directory", "file", subPackage, "packages", len(packs))
+
+	skipFiles := []string{}
 
-	skipFiles := []string{"\n"}
+	// ParseDir 返回map, 按照包名和文件路径排序, 保证每次扫描的顺序相同
+	packNames := make([]string, 0, len(packs))
+	for name := range packs {
+		packNames = append(packNames, name)
+	}
+	sort.Strings(packNames)
 
-	for _, pack := range packs {
+	for _, packName := range packNames {
+		pack := packs[packName]
 		packageName := pack.Name
 
-		log.Printf("package name: %s, file count: %d\n", packageName, len(pack.Files))
-		for filePath, f := range pack.Files {
+		filePaths := make([]string, 0, len(pack.Files))
+		for filePath := range pack.Files {
+			filePaths = append(filePaths, filePath)
+		}
+		sort.Strings(filePaths)
+
+		logDebug("scan the package", "package", packageName, "files", len(pack.Files))
+		for _, filePath := range filePaths {
+			f := pack.Files[filePath]
 			// 忽略指定的路径
 			if len(filterFilePath) > 0 && strings.LastIndex(filePath, filterFilePath) > 0 {
-				log.Println("skip file which is specified by -filterFilePath:", filePath)
+				logDebug("skip the file", "file", filePath, "reason", "specified by -filterFilePath")
 				continue
 			}
 
 			// 忽略测试文件和deprecated的资源
 			if strings.LastIndex(filePath, "test.go") > 0 || isDeprecatedFile(filePath) ||
 				isInternalFile(filePath) {
-				log.Println("skip file which is deprecated, internal or testing:", filePath)
+				logDebug("skip the file", "file", filePath, "reason", "deprecated, internal or testing")
 				continue
 			}
 
 			// 忽略非resource和data source文件
 			if strings.LastIndex(filePath, "resource_huaweicloud_") == -1 &&
 				strings.LastIndex(filePath, "data_source_huaweicloud_") == -1 {
-				log.Println("skip file which is neither resource nor data source:", filePath)
+				logDebug("skip the file", "file", filePath, "reason", "neither resource nor data source")
 				skipFiles = append(skipFiles, filePath)
 				continue
 			}
 
 			// 忽略自动生成的文件
-			if isAutoGenetatedFile(filePath) {
-				log.Println("skip file which is auto generrated", filePath)
+			autoGenerated, err := isAutoGenetatedFile(filePath)
+			if err != nil {
+				reportScanError(stageResource, filePath, err)
+				continue
+			}
+			if autoGenerated {
+				logDebug("skip the file", "file", filePath, "reason", "auto generated")
 				skipFiles = append(skipFiles, filePath)
 				continue
 			}
@@ -182,41 +245,75 @@ func searchPackage(subPackage string, publicFuncs, rsNames, dsNames []string, pr
 
 			// 根据provider提供的资源，过滤资源
 			if rsName, ok := isExportResource(resourceName, provider, rsNames, dsNames); ok {
-				log.Printf("parse file %s in %s package ...\n", resourceName, packageName)
-
-				// 优先解析golangsdk, 不支持混用的情况
-				var yarmStr string
-				if withGolangSDK(f) {
-					yarmStr = buildYaml(parseResourceFile(resourceName, filePath, f, set, publicFuncs, rsName))
-				} else {
-					yarmStr = buildYamlWithoutBase(parseResourceFile2(resourceName, filePath, f, set, publicFuncs, rsName))
-				}
+				setLogResource(rsName, filePath)
+				logInfo("parse the resource file", "package", packageName)
 
-				// 保存描述文件
-				outputFile := outputDir + strings.Replace(rsName, "huaweicloud", provider, -1) + ".yaml"
-				err := os.WriteFile(outputFile, []byte(yarmStr), 0664)
-				if err == nil {
-					log.Println("写入成功", outputFile)
+				if err := scanResourceFile(resourceName, filePath, f, set, publicFuncs, rsName); err != nil {
+					reportScanError(stageResource, filePath, err)
 				}
+				setLogResource("", "")
 
 			} else {
-				log.Println("skip file which not export:", filePath)
+				logDebug("skip the file", "file", filePath, "reason", "not exported by the provider")
 				skipFiles = append(skipFiles, filePath)
 				continue
 			}
 		}
 	}
 
-	// 写入跳过的文件
+	// 写入跳过的文件, 每行一个
+	if len(skipFiles) == 0 {
+		return
+	}
+	sort.Strings(skipFiles)
 	fSkip, fskipErr := os.OpenFile(outputDir+"skip_files.txt", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
 	if fskipErr != nil {
+		reportScanError(stageOutput, outputDir+"skip_files.txt", fskipErr)
 		return
 	}
-	_, err = fSkip.Write([]byte(strings.Join(skipFiles, "\n")))
+	if _, err = fSkip.Write([]byte(strings.Join(skipFiles, "\n") + "\n")); err != nil {
+		reportScanError(stageOutput, outputDir+"skip_files.txt", err)
+	}
 	fSkip.Close()
 
 }
 
+// scanResourceFile 解析一个资源文件并写入API描述文件, 解析时的panic也作为错误返回
+func scanResourceFile(resourceName, filePath string, f *ast.File, set *token.FileSet, publicFuncs []string,
+	rsName string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	// 优先解析golangsdk, 不支持混用的情况
+	var yarmStr string
+	if withGolangSDK(f) {
+		name, description, allURI, rpath, newName, err := parseResourceFile(resourceName, filePath, f, set, publicFuncs, rsName)
+		if err != nil {
+			return err
+		}
+		yarmStr = buildYaml(name, description, allURI, rpath, newName)
+	} else {
+		name, description, allURI, rpath, newName, err := parseResourceFile2(resourceName, filePath, f, set, publicFuncs, rsName)
+		if err != nil {
+			return err
+		}
+		yarmStr = buildYamlWithoutBase(name, description, allURI, rpath, newName)
+	}
+
+	// 保存描述文件
+	outputFile := outputDir + strings.Replace(rsName, "huaweicloud", provider, -1) + ".yaml"
+	if err := os.WriteFile(outputFile, []byte(yarmStr), 0664); err != nil {
+		reportScanError(stageOutput, outputFile, err)
+		return nil
+	}
+
+	logInfo("write the API file", "output_file", outputFile)
+	return nil
+}
+
 func isSkipDirectory(path string) bool {
 	var skipKeys = []string{
 		"acceptance", "utils", "internal", "helper", "deprecated",
@@ -300,23 +397,26 @@ func isInternalFile(filePath string) bool {
 	return false
 }
 
-func isAutoGenetatedFile(filePath string) bool {
+func isAutoGenetatedFile(filePath string) (bool, error) {
 	var offset int = 200
 
 	fileBytes, err := os.ReadFile(filePath)
 	if err != nil {
-		log.Fatal(err)
+		return false, err
 	}
 
+	// 文件长度可能小于 offset
+	if len(fileBytes) < offset {
+		offset = len(fileBytes)
+	}
 	header := string(fileBytes[:offset])
-	return strings.Contains(header, "*** AUTO GENERATED CODE ***")
+	return strings.Contains(header, "*** AUTO GENERATED CODE ***"), nil
 }
 
 func withGolangSDK(astFile *ast.File) bool {
-	sdkFilePreFix := "github.com/chnsz/golangsdk/openstack/"
 	for _, d := range astFile.Imports {
 		fullPath := strings.Trim(d.Path.Value, `"`)
-		if strings.Contains(fullPath, sdkFilePreFix) {
+		if strings.Contains(fullPath, golangsdkPrefix) {
 			return true
 		}
 	}
@@ -325,24 +425,29 @@ func withGolangSDK(astFile *ast.File) bool {
 
 func buildYaml(resourceName, description string, cloudUri []CloudUri, filePath, newResourceName string) string {
 	var tags = []string{}
+	var operations = []operationReport{}
 
-	var paths string
+	// 从文件名中获取未知产品的 catalog, 确定完整路径后重新去重和排序, 保证相同路径的请求相邻
 	for i, item := range cloudUri {
-		resourcesType := item.serviceCatalog.Product
-
-		// 从文件名中获取 catalog
-		if resourcesType == "" || resourcesType == "unknown" {
+		if item.serviceCatalog.Product == "" || item.serviceCatalog.Product == "unknown" {
 			newCatalog, newType := getCatalogFromName(filePath)
-			log.Printf("[WARN] file %s maybe belongs to %s catalog\n", filePath, newType)
-			resourcesType = newType
+			logWarn("the product is unknown, use the catalog from file name", "product", newType)
 			if newCatalog != nil {
-				item.serviceCatalog = *newCatalog
+				cloudUri[i].serviceCatalog = *newCatalog
+			} else {
+				cloudUri[i].serviceCatalog.Product = newType
 			}
 		}
+	}
+	cloudUri = removeDuplicateCloudUri(cloudUri)
+
+	var paths string
+	for i, item := range cloudUri {
+		resourcesType := item.serviceCatalog.Product
 
 		// VPC和EIP共用一个endpoint, 使用URL进行区分
 		if resourcesType == "VPC" && hasEIP(item.url) {
-			log.Printf("[DEBUG] update product VPC to EIP because the URI is %s", item.url)
+			logDebug("update product VPC to EIP", "url", item.url)
 			resourcesType = "EIP"
 		}
 
@@ -350,44 +455,51 @@ func buildYaml(resourceName, description string, cloudUri []CloudUri, filePath,
 		resourcesType = fixProduct(resourcesType, filePath)
 
 		tags = append(tags, resourcesType)
-
-		resourceBase := "/"
-		if item.serviceCatalog.Version != "" {
-			resourceBase = resourceBase + item.serviceCatalog.Version + "/"
-		}
-
-		if !item.serviceCatalog.WithOutProjectID {
-			resourceBase = resourceBase + "{project_id}/"
-		}
-
-		if item.serviceCatalog.ResourceBase != "" {
-			resourceBase = resourceBase + item.serviceCatalog.ResourceBase + "/"
-		}
+		item.category = operationCategory(item)
+		item.jobStatus = isJobStatusQuery(item)
+
+		resourceBase := resourceBasePath(item.serviceCatalog)
+		item.sensitive = matchRiskRules(resourcesType, resourceBase+item.url)
+
+		operations = append(operations, operationReport{
+			Method:      item.httpMethod,
+			Path:        resourceBase + item.url,
+			Tag:         resourcesType,
+			OperationId: item.operationId,
+			Action:      item.action,
+			Category:    item.category,
+			Sensitive:   item.sensitive,
+			Pagination:  item.pagination,
+			Polling:     item.polling,
+			JobStatus:   item.jobStatus,
+		})
 
 		isSameWithPre := isSameWithPre(cloudUri, i)
 		if isSameWithPre {
 			var yamlTemplate = fmt.Sprintf(`
     %s:
       tag: %s
-      operationId: %s`, item.httpMethod, resourcesType, item.operationId)
+      operationId: %s%s`, item.httpMethod, resourcesType, item.operationId,
+				buildOperationExtras(resourceBase+item.url, item))
 			paths = paths + yamlTemplate
 		} else {
 			var yamlTemplate = fmt.Sprintf(`
   %s:
     %s:
       tag: %s
-      operationId: %s`, resourceBase+item.url, item.httpMethod, resourcesType, item.operationId)
+      operationId: %s%s`, pathKey(resourceBase+item.url, item.action), item.httpMethod, resourcesType, item.operationId,
+				buildOperationExtras(resourceBase+item.url, item))
 			paths = paths + yamlTemplate
 		}
 	}
 
 	tags = removeDuplicateValues(tags)
-	//如果有多个tags,则找到关键资源
+	//如果有多个tags,则找到关键资源, 放在第一个, 其他的tags为资源依赖的服务
 	if len(tags) > 1 {
 		var mainTag string
 		for _, v := range tags {
 			if v == "" {
-				log.Printf("[WARN] some tags in %s is empty, please check it", resourceName)
+				logWarn("some tags are empty, please check it")
 				continue
 			}
 
@@ -403,19 +515,27 @@ func buildYaml(resourceName, description string, cloudUri []CloudUri, filePath,
 			"resource_huaweicloud_vpc_eip_associate":     "EIP",
 		}
 		if product, ok := mainProductMap[resourceName]; ok {
-			log.Printf("[DEBUG] the main tag of %s should be %s", resourceName, product)
+			logDebug("use the specified main tag", "tag", product)
 			mainTag = product
 		}
 
 		if mainTag == "" {
-			log.Printf("[WARN] can not find the main tag of %s, try to get it by path", resourceName)
+			logWarn("can not find the main tag, try to get it by path")
 			_, product := getCatalogFromName(filePath)
 			mainTag = fixProduct(product, filePath)
 		}
 
-		tags = []string{mainTag}
+		tags = withMainTag(mainTag, tags)
 	}
 
+	title := strings.Replace(newResourceName, "huaweicloud", provider, -1)
+	recorder.addResource(&resourceReport{
+		Name:       title,
+		File:       filePath,
+		Tags:       append([]string{}, tags...),
+		Operations: operations,
+	})
+
 	for i, v := range tags {
 		tags[i] = fmt.Sprintf("\n  - name: %s", v)
 	}
@@ -424,18 +544,20 @@ func buildYaml(resourceName, description string, cloudUri []CloudUri, filePath,
   version: %s
   title: %s
   description: %s
+  x-operation-id-scheme: %s
 schemes:
   - https
 host: huaweicloud.com
 tags:%s
 paths:%s
-`, version, strings.Replace(newResourceName, "huaweicloud", provider, -1), description, strings.Join(tags, ""), paths)
+`, version, title, yamlString(description), yamlString(operationIdScheme), strings.Join(tags, ""), paths)
 	return yamlTemplate
 }
 
 // 处理使用huaweicloud-sdk-go-v3的情况
 func buildYamlWithoutBase(resourceName, description string, cloudUri []CloudUri, filePath, newResourceName string) string {
 	var tags = []string{}
+	var operations = []operationReport{}
 
 	var paths string
 	for i, item := range cloudUri {
@@ -444,7 +566,7 @@ func buildYamlWithoutBase(resourceName, description string, cloudUri []CloudUri,
 		// 从文件名中获取 catalog
 		if resourcesType == "" || resourcesType == "unknown" {
 			newCatalog, newType := getCatalogFromName(filePath)
-			log.Printf("[WARN] file %s maybe belongs to %s catalog\n", filePath, newType)
+			logWarn("the product is unknown, use the catalog from file name", "product", newType)
 			resourcesType = newType
 			if newCatalog != nil {
 				item.serviceCatalog = *newCatalog
@@ -453,7 +575,7 @@ func buildYamlWithoutBase(resourceName, description string, cloudUri []CloudUri,
 
 		// VPC和EIP共用一个endpoint, 使用URL进行区分
 		if resourcesType == "VPC" && hasEIP(item.url) {
-			log.Printf("[DEBUG] update product VPC to EIP because the URI is %s", item.url)
+			logDebug("update product VPC to EIP", "url", item.url)
 			resourcesType = "EIP"
 		}
 
@@ -461,31 +583,48 @@ func buildYamlWithoutBase(resourceName, description string, cloudUri []CloudUri,
 		resourcesType = fixProduct(resourcesType, filePath)
 
 		tags = append(tags, resourcesType)
+		item.category = operationCategory(item)
+		item.jobStatus = isJobStatusQuery(item)
+
+		item.sensitive = matchRiskRules(resourcesType, item.url)
+		operations = append(operations, operationReport{
+			Method:      item.httpMethod,
+			Path:        item.url,
+			Tag:         resourcesType,
+			OperationId: item.operationId,
+			Action:      item.action,
+			Category:    item.category,
+			Sensitive:   item.sensitive,
+			Pagination:  item.pagination,
+			Polling:     item.polling,
+			JobStatus:   item.jobStatus,
+		})
 
 		isSameWithPre := isSameWithPre(cloudUri, i)
 		if isSameWithPre {
 			var yamlTemplate = fmt.Sprintf(`
     %s:
       tag: %s
-      operationId: %s`, item.httpMethod, resourcesType, item.operationId)
+      operationId: %s%s`, item.httpMethod, resourcesType, item.operationId, buildOperationExtras(item.url, item))
 			paths = paths + yamlTemplate
 		} else {
 			var yamlTemplate = fmt.Sprintf(`
   %s:
     %s:
       tag: %s
-      operationId: %s`, item.url, item.httpMethod, resourcesType, item.operationId)
+      operationId: %s%s`, pathKey(item.url, item.action), item.httpMethod, resourcesType, item.operationId,
+				buildOperationExtras(item.url, item))
 			paths = paths + yamlTemplate
 		}
 	}
 
 	tags = removeDuplicateValues(tags)
-	//如果有多个tags,则找到关键资源
+	//如果有多个tags,则找到关键资源, 放在第一个, 其他的tags为资源依赖的服务
 	if len(tags) > 1 {
 		var mainTag string
 		for _, v := range tags {
 			if v == "" {
-				log.Printf("[WARN] some tags in %s is empty, please check it", resourceName)
+				logWarn("some tags are empty, please check it")
 				continue
 			}
 
@@ -496,10 +635,20 @@ func buildYamlWithoutBase(resourceName, description string, cloudUri []CloudUri,
 		}
 
 		if mainTag != "" {
-			tags = []string{mainTag}
+			tags = withMainTag(mainTag, tags)
+		} else {
+			sort.Strings(tags)
 		}
 	}
 
+	title := strings.Replace(newResourceName, "huaweicloud", provider, -1)
+	recorder.addResource(&resourceReport{
+		Name:       title,
+		File:       filePath,
+		Tags:       append([]string{}, tags...),
+		Operations: operations,
+	})
+
 	for i, v := range tags {
 		tags[i] = fmt.Sprintf("\n  - name: %s", v)
 	}
@@ -508,12 +657,13 @@ func buildYamlWithoutBase(resourceName, description string, cloudUri []CloudUri,
   version: %s
   title: %s
   description: %s
+  x-operation-id-scheme: %s
 schemes:
   - https
 host: huaweicloud.com
 tags:%s
 paths:%s
-`, version, strings.Replace(newResourceName, "huaweicloud", provider, -1), description, strings.Join(tags, ""), paths)
+`, version, title, yamlString(description), yamlString(operationIdScheme), strings.Join(tags, ""), paths)
 	return yamlTemplate
 }
 
@@ -548,13 +698,20 @@ var specialResourceKeyMap = map[string]string{
 
 func fixProduct(resourcesType, curFilePath string) string {
 	if v, ok := specialResourceTypes[resourcesType]; ok {
-		log.Printf("[WARN] update product %s to %s in %s", resourcesType, v, curFilePath)
+		logWarn("update the product", "product", resourcesType, "target", v, "file", curFilePath)
 		return v
 	}
 
-	for k, v := range specialResourceKeyMap {
+	// 按照key排序匹配, 保证结果稳定
+	keys := make([]string, 0, len(specialResourceKeyMap))
+	for k := range specialResourceKeyMap {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := specialResourceKeyMap[k]
 		if strings.Contains(curFilePath, k) {
-			log.Printf("[DEBUG] update product %s to %s in %s", resourcesType, v, curFilePath)
+			logDebug("update the product", "product", resourcesType, "target", v, "file", curFilePath)
 			return v
 		}
 	}
@@ -577,12 +734,22 @@ func isSpecifyName(files []string, newProduct, orignalName, curFilePath string)
 	return orignalName, ok
 }
 
+// pathKey 返回 paths 中的键, 共用路径的 action 请求使用 path#action 区分
+func pathKey(path, action string) string {
+	if action == "" {
+		return path
+	}
+	return path + "#" + action
+}
+
 func isSameWithPre(cloudUri []CloudUri, curIndex int) bool {
 	if curIndex == 0 {
 		return false
 	}
 
-	if cloudUri[curIndex].url == cloudUri[curIndex-1].url {
+	// 比较完整路径, 不同版本的 client 可能构造相同的相对路径
+	cur, pre := cloudUri[curIndex], cloudUri[curIndex-1]
+	if fullPath(cur) == fullPath(pre) && cur.action == pre.action {
 		return true
 	}
 
@@ -593,14 +760,12 @@ func isSameWithPre(cloudUri []CloudUri, curIndex int) bool {
 func parseSchemaInfo(schemaJsonPath, provider string) (rsNames []string, dsNames []string, err error) {
 	input, err := os.ReadFile(schemaJsonPath)
 	if err != nil {
-		fmt.Println(err)
 		return
 	}
 
 	var mapResult map[string]interface{}
 
 	if err = json.Unmarshal(input, &mapResult); err != nil {
-		fmt.Println(err)
 		return
 	}
 
@@ -636,6 +801,9 @@ func parseSchemaInfo(schemaJsonPath, provider string) (rsNames []string, dsNames
 		rsNames = append(rsNames, strings.Replace("huaweicloud_vpc_route_table_route", "huaweicloud", provider, -1))
 		rsNames = append(rsNames, strings.Replace("huaweicloud_rds_configuration", "huaweicloud", provider, -1))
 	}
+	// schema 中的资源是map, 排序后输出
+	sort.Strings(rsNames)
+	sort.Strings(dsNames)
 
 	os.WriteFile("resource_name.txt", []byte(strings.Join(rsNames, "\n")), 0644)
 	os.WriteFile("data_source_name.txt", []byte(strings.Join(dsNames, "\n")), 0644)
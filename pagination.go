@@ -0,0 +1,91 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// 分页的方式
+const (
+	paginationMarker = "marker"
+	paginationOffset = "offset"
+	paginationPage   = "page"
+	paginationLink   = "link"
+)
+
+// golangsdk pagination 包中 XxxPage 嵌入的基础类型对应的分页方式, SinglePageBase 只有一页, 不是分页查询
+var pageBasePaginations = map[string]string{
+	"MarkerPageBase": paginationMarker,
+	"LinkedPageBase": paginationLink,
+	"OffsetPageBase": paginationOffset,
+	"PageSizeBase":   paginationPage,
+	"SinglePageBase": "",
+}
+
+// pagerPagination 返回 pagination.NewPager 的分页方式
+// 根据 XxxPage 嵌入的基础类型确定, 无法确定时根据 ListOpts 中的query参数确定
+func (e *urlEvaluator) pagerPagination(fn *ast.FuncDecl, queryParams []apiParameter) string {
+	if match := pageTypeReg.FindStringSubmatch(e.funcSource(fn)); len(match) > 1 {
+		if st := e.structType(match[1]); st != nil {
+			for _, field := range st.Fields.List {
+				sel, ok := field.Type.(*ast.SelectorExpr)
+				if !ok || len(field.Names) > 0 {
+					continue
+				}
+				if style, ok := pageBasePaginations[sel.Sel.Name]; ok {
+					return style
+				}
+			}
+		}
+	}
+
+	if style := parametersPagination(queryParams); style != "" {
+		return style
+	}
+	// 无法确定分页方式的 pager 按照链接处理
+	return paginationLink
+}
+
+// parametersPagination 根据query参数确定分页方式, 不支持分页时返回空
+func parametersPagination(params []apiParameter) string {
+	names := []string{}
+	for _, p := range params {
+		if p.in == "query" {
+			names = append(names, strings.ToLower(strings.ReplaceAll(p.name, "_", "")))
+		}
+	}
+
+	switch {
+	case sliceContains(names, "marker"):
+		return paginationMarker
+	case sliceContains(names, "offset"):
+		return paginationOffset
+	case sliceContains(names, "page") || sliceContains(names, "pageno") || sliceContains(names, "pagenum") ||
+		sliceContains(names, "pagenumber"):
+		return paginationPage
+	}
+	return ""
+}
+
+// isInLoop 判断函数中的调用是否在 for 循环中
+func isInLoop(fn *ast.FuncDecl, pos token.Pos) bool {
+	if fn.Body == nil {
+		return false
+	}
+
+	found := false
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if found || n == nil {
+			return false
+		}
+		switch loop := n.(type) {
+		case *ast.ForStmt:
+			found = loop.Body.Pos() <= pos && pos < loop.Body.End()
+		case *ast.RangeStmt:
+			found = loop.Body.Pos() <= pos && pos < loop.Body.End()
+		}
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// apiParameter API的path、query、header参数
+type apiParameter struct {
+	name      string
+	in        string // path, query, header, formData
+	required  bool
+	typ       string
+	itemsType string // typ 为 array 时元素的类型
+}
+
+// swaggerType 将Go类型转换为swagger中的基本类型, 非内置类型(例如SDK中的枚举)作为 string 处理
+func swaggerType(expr ast.Expr) (typ string, itemsType string) {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return swaggerType(t.X)
+	case *ast.ArrayType:
+		itemsType, _ = swaggerType(t.Elt)
+		return "array", itemsType
+	case *ast.MapType:
+		return "object", ""
+	case *ast.Ident:
+		switch t.Name {
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+			return "integer", ""
+		case "float32", "float64":
+			return "number", ""
+		case "bool":
+			return "boolean", ""
+		}
+	}
+	return "string", ""
+}
+
+// buildOperationExtras 生成operation中 operationId 之后的内容, 与 operationId 保持相同的缩进
+// path 为最终输出的路径, 其中的占位符都作为path参数输出
+func buildOperationExtras(path string, item CloudUri) string {
+	var extras string
+	item.parameters = withPathParameters(path, item.parameters)
+
+	if item.summary != "" {
+		extras += fmt.Sprintf(`
+      summary: %s`, yamlString(item.summary))
+	}
+
+	if item.action != "" {
+		extras += fmt.Sprintf(`
+      x-action: %s`, item.action)
+	}
+	extras += buildRiskExtras(item)
+	if item.pagination != "" {
+		extras += fmt.Sprintf(`
+      x-pagination: %s`, item.pagination)
+	}
+	if item.polling != "" {
+		extras += fmt.Sprintf(`
+      x-polling: %s`, item.polling)
+	}
+	if item.jobStatus {
+		extras += `
+      x-job-status: true`
+	}
+	extras += buildProvenance(item)
+
+	if item.consumes != "" {
+		extras += fmt.Sprintf(`
+      consumes:
+        - %s`, item.consumes)
+	}
+
+	if len(item.parameters) > 0 {
+		params := []string{}
+		for _, p := range item.parameters {
+			param := fmt.Sprintf(`
+        - name: %s
+          in: %s
+          required: %t
+          type: %s`, p.name, p.in, p.required, p.typ)
+			if p.typ == "array" {
+				param += fmt.Sprintf(`
+          items:
+            type: %s`, p.itemsType)
+			}
+			params = append(params, param)
+		}
+		extras += `
+      parameters:` + strings.Join(params, "")
+	}
+
+	if item.requestBody != nil {
+		extras += `
+      requestBody:
+        schema:` + renderSchema(item.requestBody, 10)
+	}
+
+	if len(item.statusCodes) > 0 {
+		extras += `
+      responses:`
+		for _, code := range item.statusCodes {
+			extras += fmt.Sprintf(`
+        "%d":
+          description: %s`, code, http.StatusText(code))
+			if item.responseBody != nil {
+				extras += `
+          schema:` + renderSchema(item.responseBody, 12)
+			}
+		}
+	} else if item.responseBody != nil {
+		// 没有声明成功的状态码时(eg: huaweicloud-sdk-go-v3 的 meta 文件), 响应体作为 default 响应
+		extras += `
+      responses:
+        default:
+          description: the status codes are not declared in the SDK
+          schema:` + renderSchema(item.responseBody, 12)
+	}
+
+	return extras
+}
+
+// localTypeName 返回本包中的类型名称, 例如 ListOpts, *ListOpts, 其他包中的类型返回空
+func localTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return localTypeName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// queryParameters 根据函数参数中 opts 的类型解析 q:"..." 标签定义的query参数
+// opts 的类型为 ListOptsBuilder 等接口时, 使用包中实现了该接口方法的结构体
+func (e *urlEvaluator) queryParameters(fn *ast.FuncDecl) []apiParameter {
+	params := []apiParameter{}
+	names := []string{}
+	for _, field := range fn.Type.Params.List {
+		for _, structName := range e.optsStructs(localTypeName(field.Type)) {
+			for _, p := range parseQueryTags(e.types[structName].Type.(*ast.StructType)) {
+				if !sliceContains(names, p.name) {
+					names = append(names, p.name)
+					params = append(params, p)
+				}
+			}
+		}
+	}
+	return params
+}
+
+// optsStructs 返回类型对应的结构体名称, 接口类型返回所有实现了它的方法的结构体
+func (e *urlEvaluator) optsStructs(typeName string) []string {
+	typeSpec, ok := e.types[typeName]
+	if !ok {
+		return nil
+	}
+
+	switch t := typeSpec.Type.(type) {
+	case *ast.StructType:
+		return []string{typeName}
+	case *ast.InterfaceType:
+		structNames := []string{}
+		for _, m := range t.Methods.List {
+			for _, name := range m.Names {
+				for recvType := range e.methods {
+					if e.findMethod(recvType, name.Name) != nil && !sliceContains(structNames, recvType) {
+						structNames = append(structNames, recvType)
+					}
+				}
+			}
+		}
+		sort.Strings(structNames)
+
+		structs := []string{}
+		for _, name := range structNames {
+			if ts, ok := e.types[name]; ok {
+				if _, ok := ts.Type.(*ast.StructType); ok {
+					structs = append(structs, name)
+				}
+			}
+		}
+		return structs
+	}
+	return nil
+}
+
+// findMethod 查找类型的方法
+func (e *urlEvaluator) findMethod(typeName, methodName string) *ast.FuncDecl {
+	for _, m := range e.methods[typeName] {
+		if m.Name.Name == methodName {
+			return m
+		}
+	}
+	return nil
+}
+
+// parseQueryTags 解析结构体中的 q 标签, eg: Limit int `q:"limit" required:"true"`
+func parseQueryTags(st *ast.StructType) []apiParameter {
+	params := []apiParameter{}
+	for _, field := range st.Fields.List {
+		if field.Tag == nil || len(field.Names) == 0 {
+			continue
+		}
+		tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
+		name := strings.Split(tag.Get("q"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		param := apiParameter{name: name, in: "query", required: tag.Get("required") == "true"}
+		param.typ, param.itemsType = swaggerType(field.Type)
+		params = append(params, param)
+	}
+	return params
+}
+
+var pathPlaceholderReg = regexp.MustCompile(`\{(\w+)\}`)
+
+// snakePathPlaceholders 将路径中的占位符转换为 snake_case, eg: {serverID} -> {server_id}
+func snakePathPlaceholders(uri string) string {
+	return pathPlaceholderReg.ReplaceAllStringFunc(uri, func(s string) string {
+		return "{" + toSnakeCase(s[1:len(s)-1]) + "}"
+	})
+}
+
+// toSnakeCase eg: serverID -> server_id, ResourceType -> resource_type, HTTPServer -> http_server
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				sb.WriteByte('_')
+			}
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
+
+// withPathParameters 根据最终的路径补充path参数, path参数按照路径中的顺序排在最前面
+func withPathParameters(path string, params []apiParameter) []apiParameter {
+	rst := []apiParameter{}
+	names := []string{}
+	for _, match := range pathPlaceholderReg.FindAllStringSubmatch(path, -1) {
+		if sliceContains(names, match[1]) {
+			continue
+		}
+		names = append(names, match[1])
+
+		param := apiParameter{name: match[1], in: "path", required: true, typ: "string"}
+		for _, p := range params {
+			if p.in == "path" && p.name == match[1] {
+				param = p
+				break
+			}
+		}
+		rst = append(rst, param)
+	}
+
+	for _, p := range params {
+		if p.in != "path" {
+			rst = append(rst, p)
+		}
+	}
+	return rst
+}
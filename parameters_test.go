@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestToSnakeCase(t *testing.T) {
+	cases := map[string]string{
+		"id":           "id",
+		"serverID":     "server_id",
+		"ResourceType": "resource_type",
+		"instance_id":  "instance_id",
+		"HTTPServer":   "http_server",
+		"vpcid":        "vpcid",
+		"v2Id":         "v2_id",
+	}
+	for name, want := range cases {
+		if got := toSnakeCase(name); got != want {
+			t.Errorf("toSnakeCase(%s) = %s, want %s", name, got, want)
+		}
+	}
+}
+
+func TestQueryParameters(t *testing.T) {
+	defer setupFixtureScan(t)()
+
+	evaluator, err := getURLEvaluator(basePath + "vendor/" + golangsdkPrefix + "ecs/v1/flavors")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// List(client, opts ListOptsBuilder) 使用实现了 ToFlavorListQuery 的 ListOpts
+	got := []string{}
+	for _, p := range evaluator.queryParameters(evaluator.funcs["List"]) {
+		got = append(got, fmt.Sprintf("%s:%s:%t", p.name, p.typ, p.required))
+	}
+	want := []string{"availability_zone:string:true", "limit:integer:false", "marker:string:false"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("unexpected query parameters:\n got: %v\nwant: %v", got, want)
+	}
+
+	params := withPathParameters("/v1/{project_id}/servers/{server_id}", []apiParameter{
+		{name: "limit", in: "query", typ: "integer"},
+		{name: "server_id", in: "path", required: true, typ: "integer"},
+	})
+	got = []string{}
+	for _, p := range params {
+		got = append(got, p.in+":"+p.name+":"+p.typ)
+	}
+	want = []string{"path:project_id:string", "path:server_id:integer", "query:limit:integer"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("unexpected parameters:\n got: %v\nwant: %v", got, want)
+	}
+}
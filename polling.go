@@ -0,0 +1,74 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// 运行时会重复发起的调用
+const (
+	pollingWaiter = "waiter" // resource.StateChangeConf 的 Refresh 函数中的调用
+	pollingRetry  = "retry"  // resource.Retry、WithRetry 等重试中的调用
+)
+
+// 查询异步任务状态的路径, eg: jobs/{job_id}, v1/{project_id}/jobs/{job_id}/status
+var jobStatusUrlReg = regexp.MustCompile(`(?i)(^|/)jobs/\{[^/}]+\}(/status)?$`)
+
+// callPolling 判断函数中的调用是否会重复发起, 不重复时返回空
+//  1. 函数返回 resource.StateRefreshFunc, 或者调用在 StateChangeConf 的 Refresh 字段定义的函数中
+//  2. 调用在 resource.Retry、resource.RetryContext 的函数参数中, 或者 huaweicloud-sdk-go-v3 的 .WithRetry(...) 链式调用中
+func callPolling(fn *ast.FuncDecl, pos token.Pos) string {
+	if fn.Type.Results != nil {
+		for _, r := range fn.Type.Results.List {
+			if sel, ok := r.Type.(*ast.SelectorExpr); ok && sel.Sel.Name == "StateRefreshFunc" {
+				return pollingWaiter
+			}
+		}
+	}
+	if fn.Body == nil {
+		return ""
+	}
+
+	polling := ""
+	contains := func(n ast.Node) bool {
+		return n.Pos() <= pos && pos < n.End()
+	}
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if polling != "" || n == nil || !contains(n) {
+			return false
+		}
+
+		switch node := n.(type) {
+		case *ast.KeyValueExpr:
+			if key, ok := node.Key.(*ast.Ident); ok && key.Name == "Refresh" && contains(node.Value) {
+				polling = pollingWaiter
+			}
+		case *ast.CallExpr:
+			sel, ok := node.Fun.(*ast.SelectorExpr)
+			if !ok {
+				break
+			}
+			switch sel.Sel.Name {
+			case "WithRetry":
+				if contains(sel.X) {
+					polling = pollingRetry
+				}
+			case "Retry", "RetryContext":
+				for _, arg := range node.Args {
+					if _, ok := arg.(*ast.FuncLit); ok && contains(arg) {
+						polling = pollingRetry
+					}
+				}
+			}
+		}
+		return polling == ""
+	})
+	return polling
+}
+
+// isJobStatusQuery 判断操作是否是查询异步任务状态的请求
+func isJobStatusQuery(item CloudUri) bool {
+	return strings.EqualFold(item.httpMethod, "get") && jobStatusUrlReg.MatchString(item.url)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestCallPolling(t *testing.T) {
+	src := `package ecs
+
+func waitForServer(ctx context.Context, client *golangsdk.ServiceClient, id string) error {
+	stateConf := &resource.StateChangeConf{
+		Refresh: func() (interface{}, string, error) {
+			s, err := cloudservers.Get(client, id).Extract()
+			return s, s.Status, err
+		},
+	}
+	_, err := stateConf.WaitForStateContext(ctx)
+	return err
+}
+
+func serverRefreshFunc(client *golangsdk.ServiceClient, id string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		s, err := cloudservers.Get(client, id).Extract()
+		return s, s.Status, err
+	}
+}
+
+func deleteServer(client *golangsdk.ServiceClient, id string) error {
+	return resource.Retry(time.Minute, func() *resource.RetryError {
+		_, err := cloudservers.ForceDelete(client, id).ExtractJobResponse()
+		return resource.RetryableError(err)
+	})
+}
+
+func listVpcs(client *vpc.VpcClient) error {
+	_, err := client.ListVpcsInvoker(&model.ListVpcsRequest{}).WithRetry(3, nil, nil).Invoke()
+	return err
+}
+
+func getServer(client *golangsdk.ServiceClient, id string) error {
+	_, err := cloudservers.Get(client, id).Extract()
+	return err
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "fixture.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]string{
+		"waitForServer":     pollingWaiter,
+		"serverRefreshFunc": pollingWaiter,
+		"deleteServer":      pollingRetry,
+		"listVpcs":          pollingRetry,
+		"getServer":         "",
+	}
+	for _, d := range file.Decls {
+		fn := d.(*ast.FuncDecl)
+		// 第一个SDK调用的位置, eg: cloudservers.Get, client.ListVpcsInvoker
+		offset := strings.Index(src[fset.Position(fn.Pos()).Offset:], "cloudservers.")
+		if fn.Name.Name == "listVpcs" {
+			offset = strings.Index(src[fset.Position(fn.Pos()).Offset:], "client.ListVpcsInvoker")
+		}
+		if got := callPolling(fn, fn.Pos()+token.Pos(offset)); got != cases[fn.Name.Name] {
+			t.Errorf("callPolling(%s) = %q, want %q", fn.Name.Name, got, cases[fn.Name.Name])
+		}
+	}
+}
+
+func TestIsJobStatusQuery(t *testing.T) {
+	cases := []struct {
+		uri  CloudUri
+		want bool
+	}{
+		{CloudUri{url: "jobs/{job_id}", httpMethod: "get"}, true},
+		{CloudUri{url: "v1/{project_id}/jobs/{job_id}/status", httpMethod: "GET"}, true},
+		{CloudUri{url: "jobs/{job_id}", httpMethod: "delete"}, false},
+		{CloudUri{url: "cloudservers/{server_id}", httpMethod: "get"}, false},
+	}
+	for _, c := range cases {
+		if got := isJobStatusQuery(c.uri); got != c.want {
+			t.Errorf("isJobStatusQuery(%s %s) = %t, want %t", c.uri.httpMethod, c.uri.url, got, c.want)
+		}
+	}
+}
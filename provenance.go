@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// 命令行参数
+var withProvenance bool
+
+func init() {
+	flag.BoolVar(&withProvenance, "provenance", false,
+		"output the source of each API: x-sdk-package, x-sdk-function, x-terraform-function and x-source")
+}
+
+// sourcePosition 返回provider中代码的位置 file:line, 文件使用相对 basePath 的路径
+func sourcePosition(fset *token.FileSet, pos token.Pos) string {
+	position := fset.Position(pos)
+	fileName := strings.TrimPrefix(filepath.ToSlash(position.Filename), filepath.ToSlash(basePath))
+	return fmt.Sprintf("%s:%d", fileName, position.Line)
+}
+
+// buildProvenance 生成operation中的来源信息, 没有指定 -provenance 时为空
+func buildProvenance(item CloudUri) string {
+	if !withProvenance {
+		return ""
+	}
+
+	var rst string
+	fields := [][2]string{
+		{"x-sdk-package", item.filePath},
+		{"x-sdk-function", item.sdkFunction},
+		{"x-terraform-function", item.terraformFunction},
+		{"x-source", item.source},
+	}
+	for _, f := range fields {
+		if f[1] != "" {
+			rst += fmt.Sprintf(`
+      %s: %s`, f[0], f[1])
+		}
+	}
+	return rst
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+const coverageReportFile = "coverage_report.json"
+
+// coverageReport 扫描结果的统计报告, 写入 ${outputDir}/coverage_report.json
+type coverageReport struct {
+	Version         string            `json:"version"`
+	Summary         reportSummary     `json:"summary"`
+	Resources       []*resourceReport `json:"resources"`
+	UnresolvedCalls []unresolvedCall  `json:"unresolvedCalls"`
+	Errors          []scanError       `json:"errors"`
+	Gates           []gateResult      `json:"gates"`
+}
+
+type reportSummary struct {
+	Resources           int `json:"resources"`
+	Operations          int `json:"operations"`
+	UnresolvedCalls     int `json:"unresolvedCalls"`
+	EmptyPathResources  int `json:"emptyPathResources"`
+	EmptyTagResources   int `json:"emptyTagResources"`
+	MutatingOperations  int `json:"mutatingOperations"`
+	SensitiveOperations int `json:"sensitiveOperations"`
+	PollingOperations   int `json:"pollingOperations"`
+	Errors              int `json:"errors"`
+}
+
+// resourceReport 一个resource或者data source的扫描结果
+type resourceReport struct {
+	Name       string            `json:"name"`
+	File       string            `json:"file"`
+	Tags       []string          `json:"tags"`
+	Operations []operationReport `json:"operations"`
+	// 修改资源(write, delete, action)和敏感的API数量
+	MutatingOperations  int `json:"mutatingOperations"`
+	SensitiveOperations int `json:"sensitiveOperations"`
+	// 运行时重复发起(等待、重试、查询任务状态)的API数量
+	PollingOperations int `json:"pollingOperations"`
+}
+
+type operationReport struct {
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	Tag         string   `json:"tag"`
+	OperationId string   `json:"operationId"`
+	Action      string   `json:"action,omitempty"`
+	Category    string   `json:"category"`
+	Sensitive   []string `json:"sensitive,omitempty"`
+	Pagination  string   `json:"pagination,omitempty"`
+	Polling     string   `json:"polling,omitempty"`
+	JobStatus   bool     `json:"jobStatus,omitempty"`
+}
+
+// unresolvedCall 在SDK中找不到对应API的调用
+type unresolvedCall struct {
+	Resource   string `json:"resource"`
+	File       string `json:"file"`
+	Func       string `json:"func"`
+	SdkFunc    string `json:"sdkFunc"`
+	SdkPackage string `json:"sdkPackage"`
+}
+
+// 扫描的阶段, 用于标记错误发生的位置
+const (
+	stageConfig   = "config"
+	stagePackage  = "package"
+	stageResource = "resource"
+	stageSDK      = "sdk"
+	stageOutput   = "output"
+)
+
+// scanError 扫描过程中的错误, 出错的文件会被跳过, 不影响其他文件的扫描
+type scanError struct {
+	Stage    string `json:"stage"`
+	File     string `json:"file"`
+	Resource string `json:"resource,omitempty"`
+	Err      string `json:"error"`
+}
+
+// scanRecorder 在扫描过程中记录结果
+type scanRecorder struct {
+	mu         sync.Mutex
+	resources  map[string]*resourceReport
+	unresolved []unresolvedCall
+	errors     []scanError
+}
+
+var recorder = &scanRecorder{resources: make(map[string]*resourceReport)}
+
+func (r *scanRecorder) addResource(rs *resourceReport) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.resources[rs.Name] = rs
+}
+
+func (r *scanRecorder) addUnresolvedCall(call unresolvedCall) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// 使用当前扫描的资源补全信息
+	resource, file := currentLogResource()
+	if call.Resource == "" {
+		call.Resource = resource
+	}
+	if call.File == "" {
+		call.File = file
+	}
+	r.unresolved = append(r.unresolved, call)
+}
+
+// addError 记录扫描的错误, 相同的错误只记录一次
+func (r *scanRecorder) addError(stage, file string, err error) bool {
+	resource, _ := currentLogResource()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item := scanError{
+		Stage:    stage,
+		File:     file,
+		Resource: resource,
+		Err:      err.Error(),
+	}
+	for _, v := range r.errors {
+		if v.Stage == item.Stage && v.File == item.File && v.Err == item.Err {
+			return false
+		}
+	}
+	r.errors = append(r.errors, item)
+	return true
+}
+
+// reportScanError 输出并记录扫描的错误
+func reportScanError(stage, file string, err error) {
+	if recorder.addError(stage, file, err) {
+		// 写入输出文件失败时, 文件是输出的路径而不是扫描的源码
+		key := "file"
+		if stage == stageOutput {
+			key = "output_file"
+		}
+		logError("scan error, skip it and continue", "stage", stage, key, file, "reason", err)
+	}
+}
+
+func (r *scanRecorder) buildReport(version string) *coverageReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := coverageReport{
+		Version:         version,
+		Resources:       []*resourceReport{},
+		UnresolvedCalls: append([]unresolvedCall{}, r.unresolved...),
+		Errors:          append([]scanError{}, r.errors...),
+		Gates:           []gateResult{},
+	}
+
+	for _, rs := range r.resources {
+		rs.MutatingOperations, rs.SensitiveOperations, rs.PollingOperations = 0, 0, 0
+		for _, op := range rs.Operations {
+			if isMutating(op.Category) {
+				rs.MutatingOperations++
+			}
+			if len(op.Sensitive) > 0 {
+				rs.SensitiveOperations++
+			}
+			if op.Polling != "" || op.JobStatus {
+				rs.PollingOperations++
+			}
+		}
+		report.Resources = append(report.Resources, rs)
+		report.Summary.Operations += len(rs.Operations)
+		report.Summary.MutatingOperations += rs.MutatingOperations
+		report.Summary.SensitiveOperations += rs.SensitiveOperations
+		report.Summary.PollingOperations += rs.PollingOperations
+		if len(rs.Operations) == 0 {
+			report.Summary.EmptyPathResources++
+		}
+		if hasEmptyTag(rs) {
+			report.Summary.EmptyTagResources++
+		}
+	}
+	sort.Slice(report.Resources, func(i, j int) bool {
+		return report.Resources[i].Name < report.Resources[j].Name
+	})
+	sort.SliceStable(report.UnresolvedCalls, func(i, j int) bool {
+		a, b := report.UnresolvedCalls[i], report.UnresolvedCalls[j]
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		if a.Func != b.Func {
+			return a.Func < b.Func
+		}
+		return a.SdkFunc < b.SdkFunc
+	})
+
+	report.Summary.Resources = len(report.Resources)
+	report.Summary.UnresolvedCalls = len(report.UnresolvedCalls)
+	report.Summary.Errors = len(report.Errors)
+	return &report
+}
+
+// hasEmptyTag 资源或者API的tag为空或者unknown
+func hasEmptyTag(rs *resourceReport) bool {
+	if len(rs.Tags) == 0 {
+		return true
+	}
+	for _, tag := range rs.Tags {
+		if isEmptyTag(tag) {
+			return true
+		}
+	}
+	for _, op := range rs.Operations {
+		if isEmptyTag(op.Tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmptyTag(tag string) bool {
+	return tag == "" || tag == "unknown" || tag == "UNKNOWN"
+}
+
+func writeCoverageReport(dir string, report *coverageReport) error {
+	content, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, coverageReportFile), content, 0644)
+}
+
+func readCoverageReport(path string) (*coverageReport, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var report coverageReport
+	if err := json.Unmarshal(content, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// 操作的类型
+const (
+	categoryRead   = "read"
+	categoryWrite  = "write"
+	categoryDelete = "delete"
+	categoryAction = "action"
+)
+
+// 命令行参数
+var riskRulesPath string
+
+func init() {
+	flag.StringVar(&riskRulesPath, "riskRules", "../../config/risk_rules.json",
+		"the rules to flag the sensitive APIs by product and path")
+}
+
+// riskRule 敏感API的规则, 产品和路径都匹配时标记为敏感
+type riskRule struct {
+	Name     string   `json:"name"`               // eg: credential, key, secret, acl, policy
+	Products []string `json:"products,omitempty"` // 为空时匹配所有产品
+	Paths    []string `json:"paths"`              // 路径的正则表达式, 忽略大小写
+
+	pathRegs []*regexp.Regexp
+}
+
+var riskRules []riskRule
+
+// loadRiskRules 读取并编译敏感API的规则
+func loadRiskRules(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	rules := []riskRule{}
+	if err := json.Unmarshal(content, &rules); err != nil {
+		return err
+	}
+	for i, rule := range rules {
+		for _, p := range rule.Paths {
+			reg, err := regexp.Compile("(?i)" + p)
+			if err != nil {
+				return fmt.Errorf("invalid path of the rule %s: %s", rule.Name, err)
+			}
+			rules[i].pathRegs = append(rules[i].pathRegs, reg)
+		}
+	}
+
+	riskRules = rules
+	logDebug("loaded the risk rules", "file", path, "rules", len(rules))
+	return nil
+}
+
+// operationCategory 根据HTTP方法将操作分为 read, write, delete, 通过请求体区分的 .../action 请求为 action
+func operationCategory(item CloudUri) string {
+	if item.action != "" || isActionUrl(item.url) {
+		return categoryAction
+	}
+
+	switch strings.ToLower(item.httpMethod) {
+	case "get", "head":
+		return categoryRead
+	case "delete":
+		return categoryDelete
+	}
+	return categoryWrite
+}
+
+// isMutating 判断操作是否修改资源
+func isMutating(category string) bool {
+	return category != categoryRead
+}
+
+// matchRiskRules 返回产品和路径匹配的规则名称
+func matchRiskRules(product, path string) []string {
+	names := []string{}
+	for _, rule := range riskRules {
+		if sliceContains(names, rule.Name) || !rule.matchProduct(product) {
+			continue
+		}
+		for _, reg := range rule.pathRegs {
+			if reg.MatchString(path) {
+				names = append(names, rule.Name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+func (rule riskRule) matchProduct(product string) bool {
+	if len(rule.Products) == 0 {
+		return true
+	}
+	for _, p := range rule.Products {
+		if strings.EqualFold(p, product) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildRiskExtras 生成operation中的 x-category 和 x-sensitive
+func buildRiskExtras(item CloudUri) string {
+	if item.category == "" {
+		return ""
+	}
+
+	extras := fmt.Sprintf(`
+      x-category: %s`, item.category)
+	if len(item.sensitive) > 0 {
+		extras += `
+      x-sensitive:`
+		for _, name := range item.sensitive {
+			extras += fmt.Sprintf(`
+        - %s`, name)
+		}
+	}
+	return extras
+}
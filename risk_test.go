@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMatchRiskRules(t *testing.T) {
+	defer setupFixtureScan(t)()
+	if err := loadRiskRules(riskRulesPath); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		product string
+		path    string
+		want    []string
+	}{
+		{"ECS", "/v1/{project_id}/cloudservers/{server_id}", []string{}},
+		{"ECS", "/v2.1/{project_id}/os-keypairs/{keypair_name}", []string{"key"}},
+		{"ECS", "/v3/{project_id}/keypairs/{keypair_name}", []string{"key"}},
+		{"ECS", "/v1/{project_id}/cloudservers/{server_id}/os-reset-password", []string{"credential"}},
+		{"IAM", "/v3.0/OS-CREDENTIAL/credentials/{access_key}", []string{"credential", "policy"}},
+		{"VPC", "/v1/{project_id}/security-group-rules", []string{"acl"}},
+		{"CSMS", "/v1/{project_id}/secrets/{secret_name}/versions", []string{"secret"}},
+	}
+	for _, c := range cases {
+		if got := matchRiskRules(c.product, c.path); fmt.Sprint(got) != fmt.Sprint(c.want) {
+			t.Errorf("matchRiskRules(%s, %s) = %v, want %v", c.product, c.path, got, c.want)
+		}
+	}
+}
+
+func TestOperationCategory(t *testing.T) {
+	cases := []struct {
+		uri  CloudUri
+		want string
+	}{
+		{CloudUri{url: "cloudservers/{id}", httpMethod: "get"}, categoryRead},
+		{CloudUri{url: "cloudservers", httpMethod: "post"}, categoryWrite},
+		{CloudUri{url: "cloudservers/{id}", httpMethod: "put"}, categoryWrite},
+		{CloudUri{url: "cloudservers/{id}", httpMethod: "delete"}, categoryDelete},
+		{CloudUri{url: "cloudservers/{id}/action", httpMethod: "post", action: "os-stop"}, categoryAction},
+		{CloudUri{url: "cloudservers/{id}/tags/action", httpMethod: "POST"}, categoryAction},
+	}
+	for _, c := range cases {
+		if got := operationCategory(c.uri); got != c.want {
+			t.Errorf("operationCategory(%s %s) = %s, want %s", c.uri.httpMethod, c.uri.url, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/huaweicloud/terraform-api-scan/scan-serve/model"
+)
+
+// Snapshot 一次扫描的输出结果
+type Snapshot struct {
+	Version   string
+	Dir       string
+	Resources map[string]*Resource
+}
+
+// Resource 一个resource或者data source使用到的API
+type Resource struct {
+	Name        string      `json:"name"`
+	Kind        string      `json:"kind"`
+	Description string      `json:"description,omitempty"`
+	Products    []string    `json:"products"`
+	Operations  []Operation `json:"operations"`
+}
+
+// Operation 一个API操作, 即 method + path
+type Operation struct {
+	Resource    string                 `json:"resource,omitempty"`
+	Method      string                 `json:"method"`
+	Path        string                 `json:"path"`
+	Tag         string                 `json:"tag"`
+	OperationId string                 `json:"operationId"`
+	Extensions  map[string]interface{} `json:"extensions,omitempty"`
+}
+
+func (o Operation) key() string {
+	return strings.ToUpper(o.Method) + " " + o.Path
+}
+
+// Catalog 加载的所有扫描结果, 按照加载顺序排列, 最后一个为最新版本
+type Catalog struct {
+	Snapshots []*Snapshot
+}
+
+// loadCatalog 加载 -inputDirs 指定的扫描结果, 格式为: dir1,label2=dir2
+func loadCatalog(input string) (*Catalog, error) {
+	var catalog Catalog
+
+	for _, item := range strings.Split(input, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		label, dir := "", item
+		if index := strings.Index(item, "="); index > 0 {
+			label, dir = item[:index], item[index+1:]
+		}
+
+		snapshot, err := loadSnapshot(dir, label)
+		if err != nil {
+			return nil, err
+		}
+		if catalog.find(snapshot.Version) != nil {
+			return nil, fmt.Errorf("the version %s is loaded more than once, please specify a label for %s",
+				snapshot.Version, dir)
+		}
+
+		log.Printf("load %d resources of %s from %s\n", len(snapshot.Resources), snapshot.Version, dir)
+		catalog.Snapshots = append(catalog.Snapshots, snapshot)
+	}
+
+	if len(catalog.Snapshots) == 0 {
+		return nil, fmt.Errorf("no scan output is specified")
+	}
+	return &catalog, nil
+}
+
+func loadSnapshot(dir, label string) (*Snapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := Snapshot{
+		Version:   label,
+		Dir:       dir,
+		Resources: make(map[string]*Resource),
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+			continue
+		}
+
+		filePath := filepath.Join(dir, entry.Name())
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		var api model.Api
+		if err := yaml.Unmarshal(content, &api); err != nil {
+			log.Printf("[WARN] skip %s which is not a valid API file: %s\n", filePath, err)
+			continue
+		}
+
+		name := strings.TrimSuffix(entry.Name(), ".yaml")
+		snapshot.Resources[name] = buildResource(name, api)
+
+		if snapshot.Version == "" {
+			snapshot.Version = api.Info.Version
+		}
+	}
+
+	if snapshot.Version == "" {
+		snapshot.Version = filepath.Base(filepath.Clean(dir))
+	}
+	return &snapshot, nil
+}
+
+func buildResource(name string, api model.Api) *Resource {
+	kind := "resource"
+	if strings.HasPrefix(name, "data_source_") {
+		kind = "data_source"
+	}
+
+	rs := Resource{
+		Name:        name,
+		Kind:        kind,
+		Description: api.Info.Description,
+		Products:    []string{},
+		Operations:  []Operation{},
+	}
+
+	products := make(map[string]bool)
+	for _, tag := range api.Tags {
+		if tag.Name != "" {
+			products[tag.Name] = true
+		}
+	}
+
+	for path, methods := range api.Paths {
+		for method, op := range methods {
+			rs.Operations = append(rs.Operations, Operation{
+				Method:      strings.ToUpper(method),
+				Path:        path,
+				Tag:         op.Tag,
+				OperationId: op.OperationId,
+				Extensions:  op.Extensions,
+			})
+			if op.Tag != "" {
+				products[op.Tag] = true
+			}
+		}
+	}
+
+	sort.Slice(rs.Operations, func(i, j int) bool {
+		if rs.Operations[i].Path != rs.Operations[j].Path {
+			return rs.Operations[i].Path < rs.Operations[j].Path
+		}
+		return rs.Operations[i].Method < rs.Operations[j].Method
+	})
+
+	for k := range products {
+		rs.Products = append(rs.Products, k)
+	}
+	sort.Strings(rs.Products)
+	return &rs
+}
+
+func (c *Catalog) find(version string) *Snapshot {
+	for _, s := range c.Snapshots {
+		if s.Version == version {
+			return s
+		}
+	}
+	return nil
+}
+
+// get 返回指定版本的扫描结果, 没有指定时返回最新版本
+func (c *Catalog) get(version string) (*Snapshot, error) {
+	if version == "" {
+		return c.Snapshots[len(c.Snapshots)-1], nil
+	}
+
+	if s := c.find(version); s != nil {
+		return s, nil
+	}
+	return nil, fmt.Errorf("version %s is not loaded", version)
+}
+
+// sortedNames 返回排序后的资源名称
+func (s *Snapshot) sortedNames() []string {
+	names := make([]string, 0, len(s.Resources))
+	for k := range s.Resources {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
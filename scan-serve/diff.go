@@ -0,0 +1,69 @@
+package main
+
+// ResourceDiff 同一个资源在两个版本之间的API变化
+type ResourceDiff struct {
+	Name    string      `json:"name"`
+	Added   []Operation `json:"added"`
+	Removed []Operation `json:"removed"`
+}
+
+// SnapshotDiff 两个版本之间的API变化
+type SnapshotDiff struct {
+	From             string         `json:"from"`
+	To               string         `json:"to"`
+	AddedResources   []string       `json:"addedResources"`
+	RemovedResources []string       `json:"removedResources"`
+	ChangedResources []ResourceDiff `json:"changedResources"`
+}
+
+func diffSnapshot(from, to *Snapshot) SnapshotDiff {
+	rst := SnapshotDiff{
+		From:             from.Version,
+		To:               to.Version,
+		AddedResources:   []string{},
+		RemovedResources: []string{},
+		ChangedResources: []ResourceDiff{},
+	}
+
+	for _, name := range from.sortedNames() {
+		if _, ok := to.Resources[name]; !ok {
+			rst.RemovedResources = append(rst.RemovedResources, name)
+		}
+	}
+
+	for _, name := range to.sortedNames() {
+		oldRs, ok := from.Resources[name]
+		if !ok {
+			rst.AddedResources = append(rst.AddedResources, name)
+			continue
+		}
+
+		newRs := to.Resources[name]
+		rsDiff := ResourceDiff{
+			Name:    name,
+			Added:   subtractOperations(newRs.Operations, oldRs.Operations),
+			Removed: subtractOperations(oldRs.Operations, newRs.Operations),
+		}
+		if len(rsDiff.Added) > 0 || len(rsDiff.Removed) > 0 {
+			rst.ChangedResources = append(rst.ChangedResources, rsDiff)
+		}
+	}
+
+	return rst
+}
+
+// subtractOperations 返回在 a 中但不在 b 中的API
+func subtractOperations(a, b []Operation) []Operation {
+	keys := make(map[string]bool)
+	for _, op := range b {
+		keys[op.key()] = true
+	}
+
+	rst := []Operation{}
+	for _, op := range a {
+		if !keys[op.key()] {
+			rst = append(rst, op)
+		}
+	}
+	return rst
+}
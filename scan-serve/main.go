@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+)
+
+var (
+	// 命令行参数
+	inputDirs string
+	listen    string
+)
+
+func init() {
+	flag.StringVar(&inputDirs, "inputDirs", "./api/",
+		"the output dirs of scanning, separated by comma, and the version label can be specified by label=dir")
+	flag.StringVar(&listen, "listen", "127.0.0.1:8080", "the address to listen on")
+}
+
+func main() {
+	flag.Parse()
+
+	catalog, err := loadCatalog(inputDirs)
+	if err != nil {
+		log.Printf("[ERROR] failed to load the scan output: %s\n", err)
+		os.Exit(1)
+	}
+
+	mux, err := newServeMux(catalog)
+	if err != nil {
+		log.Printf("[ERROR] failed to build the web service: %s\n", err)
+		os.Exit(1)
+	}
+
+	log.Printf("serving %d versions on http://%s\n", len(catalog.Snapshots), listen)
+	if err := http.ListenAndServe(listen, mux); err != nil {
+		log.Printf("[ERROR] %s\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,27 @@
+package model
+
+// Api 扫描工具输出的API描述文件
+type Api struct {
+	Info    Info                            `yaml:"info,omitempty"`
+	Host    string                          `yaml:"host,omitempty"`
+	Tags    []Tag                           `yaml:"tags,omitempty"`
+	Schemes []string                        `yaml:"schemes,omitempty"`
+	Paths   map[string]map[string]Operation `yaml:"paths,omitempty"`
+}
+
+type Info struct {
+	Version     string `yaml:"version,omitempty"`
+	Title       string `yaml:"title,omitempty"`
+	Description string `yaml:"description,omitempty"`
+}
+
+type Tag struct {
+	Name string `yaml:"name,omitempty"`
+}
+
+type Operation struct {
+	Tag         string `yaml:"tag,omitempty"`
+	OperationId string `yaml:"operationId,omitempty"`
+	// 其他字段(参数、扩展字段等)原样保留
+	Extensions map[string]interface{} `yaml:",inline"`
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+//go:embed web
+var webFiles embed.FS
+
+type server struct {
+	catalog *Catalog
+}
+
+func newServeMux(catalog *Catalog) (*http.ServeMux, error) {
+	s := &server{catalog: catalog}
+
+	webRoot, err := fs.Sub(webFiles, "web")
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/versions", s.readOnly(s.handleVersions))
+	mux.HandleFunc("/api/resources", s.readOnly(s.handleResources))
+	mux.HandleFunc("/api/resources/", s.readOnly(s.handleResource))
+	mux.HandleFunc("/api/operations", s.readOnly(s.handleOperations))
+	mux.HandleFunc("/api/products", s.readOnly(s.handleProducts))
+	mux.HandleFunc("/api/diff", s.readOnly(s.handleDiff))
+	mux.Handle("/", http.FileServer(http.FS(webRoot)))
+	return mux, nil
+}
+
+// readOnly 只允许GET和HEAD请求
+func (s *server) readOnly(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeError(w, http.StatusMethodNotAllowed, "the service is read-only")
+			return
+		}
+		handler(w, r)
+	}
+}
+
+type versionSummary struct {
+	Version    string `json:"version"`
+	Dir        string `json:"dir"`
+	Resources  int    `json:"resources"`
+	Operations int    `json:"operations"`
+}
+
+func (s *server) handleVersions(w http.ResponseWriter, r *http.Request) {
+	rst := []versionSummary{}
+	for _, snapshot := range s.catalog.Snapshots {
+		item := versionSummary{
+			Version:   snapshot.Version,
+			Dir:       snapshot.Dir,
+			Resources: len(snapshot.Resources),
+		}
+		for _, rs := range snapshot.Resources {
+			item.Operations += len(rs.Operations)
+		}
+		rst = append(rst, item)
+	}
+	writeJSON(w, rst)
+}
+
+type resourceSummary struct {
+	Name        string   `json:"name"`
+	Kind        string   `json:"kind"`
+	Description string   `json:"description,omitempty"`
+	Products    []string `json:"products"`
+	Operations  int      `json:"operations"`
+}
+
+// handleResources 查询资源列表, 支持参数: version, q, product, kind
+func (s *server) handleResources(w http.ResponseWriter, r *http.Request) {
+	snapshot, ok := s.snapshot(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	rst := []resourceSummary{}
+	for _, name := range snapshot.sortedNames() {
+		rs := snapshot.Resources[name]
+		if !matchResource(rs, query.Get("q"), query.Get("product"), query.Get("kind")) {
+			continue
+		}
+
+		rst = append(rst, resourceSummary{
+			Name:        rs.Name,
+			Kind:        rs.Kind,
+			Description: rs.Description,
+			Products:    rs.Products,
+			Operations:  len(rs.Operations),
+		})
+	}
+	writeJSON(w, rst)
+}
+
+// handleResource 查询单个资源的详细信息: /api/resources/{name}
+func (s *server) handleResource(w http.ResponseWriter, r *http.Request) {
+	snapshot, ok := s.snapshot(w, r)
+	if !ok {
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/api/resources/")
+	rs, ok := snapshot.Resources[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "resource "+name+" is not found in "+snapshot.Version)
+		return
+	}
+	writeJSON(w, rs)
+}
+
+// handleOperations 查询API列表, 支持参数: version, q, product, method
+func (s *server) handleOperations(w http.ResponseWriter, r *http.Request) {
+	snapshot, ok := s.snapshot(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	keyword := strings.ToLower(query.Get("q"))
+	product := query.Get("product")
+	method := strings.ToUpper(query.Get("method"))
+
+	rst := []Operation{}
+	for _, name := range snapshot.sortedNames() {
+		for _, op := range snapshot.Resources[name].Operations {
+			if product != "" && !strings.EqualFold(op.Tag, product) {
+				continue
+			}
+			if method != "" && op.Method != method {
+				continue
+			}
+			if keyword != "" && !strings.Contains(strings.ToLower(name+" "+op.Path+" "+op.OperationId), keyword) {
+				continue
+			}
+
+			op.Resource = name
+			rst = append(rst, op)
+		}
+	}
+	writeJSON(w, rst)
+}
+
+type productSummary struct {
+	Name       string   `json:"name"`
+	Resources  []string `json:"resources"`
+	Operations int      `json:"operations"`
+}
+
+func (s *server) handleProducts(w http.ResponseWriter, r *http.Request) {
+	snapshot, ok := s.snapshot(w, r)
+	if !ok {
+		return
+	}
+
+	products := make(map[string]*productSummary)
+	for _, name := range snapshot.sortedNames() {
+		for _, op := range snapshot.Resources[name].Operations {
+			p, ok := products[op.Tag]
+			if !ok {
+				p = &productSummary{Name: op.Tag, Resources: []string{}}
+				products[op.Tag] = p
+			}
+
+			p.Operations++
+			if len(p.Resources) == 0 || p.Resources[len(p.Resources)-1] != name {
+				p.Resources = append(p.Resources, name)
+			}
+		}
+	}
+
+	rst := []*productSummary{}
+	for _, p := range products {
+		rst = append(rst, p)
+	}
+	sort.Slice(rst, func(i, j int) bool {
+		return rst[i].Name < rst[j].Name
+	})
+	writeJSON(w, rst)
+}
+
+// handleDiff 比较两个版本的API: /api/diff?from=v1.50.0&to=v1.51.0
+// 没有指定时, 比较最新的两个版本
+func (s *server) handleDiff(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	fromVersion, toVersion := query.Get("from"), query.Get("to")
+
+	if fromVersion == "" {
+		count := len(s.catalog.Snapshots)
+		if count < 2 {
+			writeError(w, http.StatusBadRequest, "at least two versions are required to compare")
+			return
+		}
+		fromVersion = s.catalog.Snapshots[count-2].Version
+	}
+
+	from, err := s.catalog.get(fromVersion)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	to, err := s.catalog.get(toVersion)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, diffSnapshot(from, to))
+}
+
+func (s *server) snapshot(w http.ResponseWriter, r *http.Request) (*Snapshot, bool) {
+	snapshot, err := s.catalog.get(r.URL.Query().Get("version"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return nil, false
+	}
+	return snapshot, true
+}
+
+func matchResource(rs *Resource, keyword, product, kind string) bool {
+	if kind != "" && rs.Kind != kind {
+		return false
+	}
+
+	if product != "" {
+		found := false
+		for _, p := range rs.Products {
+			if strings.EqualFold(p, product) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if keyword == "" {
+		return true
+	}
+
+	keyword = strings.ToLower(keyword)
+	if strings.Contains(strings.ToLower(rs.Name+" "+rs.Description), keyword) {
+		return true
+	}
+	for _, op := range rs.Operations {
+		if strings.Contains(strings.ToLower(op.Path+" "+op.OperationId), keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		log.Printf("[WARN] failed to write the response: %s\n", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// newTestServeMux 加载 testdata 中两个版本的扫描结果
+func newTestServeMux(t *testing.T) *http.ServeMux {
+	t.Helper()
+
+	log.SetOutput(io.Discard)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	catalog, err := loadCatalog("testdata/v1,v1.51.0=testdata/v2")
+	if err != nil {
+		t.Fatalf("failed to load the snapshots: %s", err)
+	}
+	mux, err := newServeMux(catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mux
+}
+
+// serve 发起请求, 并在返回码为200时解析JSON响应
+func serve(t *testing.T, mux *http.ServeMux, method, target string, v interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+	if w.Code == http.StatusOK && v != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+			t.Fatalf("invalid response of %s: %s", target, err)
+		}
+	}
+	return w
+}
+
+func operationKeys(ops []Operation) string {
+	keys := []string{}
+	for _, op := range ops {
+		keys = append(keys, op.key())
+	}
+	return strings.Join(keys, ",")
+}
+
+func TestHandleVersions(t *testing.T) {
+	mux := newTestServeMux(t)
+
+	var versions []versionSummary
+	serve(t, mux, http.MethodGet, "/api/versions", &versions)
+	// 没有指定标签时使用描述文件中的版本
+	want := "[{v1.50.0 testdata/v1 2 3} {v1.51.0 testdata/v2 2 5}]"
+	if got := fmt.Sprint(versions); got != want {
+		t.Errorf("versions = %s, want %s", got, want)
+	}
+}
+
+func TestHandleDiff(t *testing.T) {
+	mux := newTestServeMux(t)
+
+	// 没有指定版本时比较最新的两个版本
+	for _, target := range []string{"/api/diff?from=v1.50.0&to=v1.51.0", "/api/diff"} {
+		var diff SnapshotDiff
+		if w := serve(t, mux, http.MethodGet, target, &diff); w.Code != http.StatusOK {
+			t.Fatalf("%s returns %d: %s", target, w.Code, w.Body)
+		}
+
+		if diff.From != "v1.50.0" || diff.To != "v1.51.0" {
+			t.Errorf("%s compares %s with %s", target, diff.From, diff.To)
+		}
+		if got := fmt.Sprint(diff.AddedResources); got != "[resource_huaweicloud_compute_instance]" {
+			t.Errorf("added resources of %s = %s", target, got)
+		}
+		if got := fmt.Sprint(diff.RemovedResources); got != "[data_source_huaweicloud_vpcs]" {
+			t.Errorf("removed resources of %s = %s", target, got)
+		}
+		if len(diff.ChangedResources) != 1 || diff.ChangedResources[0].Name != "resource_huaweicloud_vpc" ||
+			operationKeys(diff.ChangedResources[0].Added) != "DELETE /v1/{project_id}/vpcs/{id}" ||
+			len(diff.ChangedResources[0].Removed) != 0 {
+			t.Errorf("unexpected changed resources of %s: %+v", target, diff.ChangedResources)
+		}
+	}
+
+	if w := serve(t, mux, http.MethodGet, "/api/diff?from=v0.1.0", nil); w.Code != http.StatusNotFound {
+		t.Errorf("expect 404 for an unknown version, got %d", w.Code)
+	}
+}
+
+func TestHandleOperations(t *testing.T) {
+	mux := newTestServeMux(t)
+
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{"product=ecs", "POST /v1/{project_id}/cloudservers"},
+		// 同一个资源可以使用多个产品的API
+		{"product=VPC&method=get", "GET /v1/{project_id}/subnets/{id},GET /v1/{project_id}/vpcs/{id}"},
+		{"q=vpcs.delete", "DELETE /v1/{project_id}/vpcs/{id}"},
+		{"version=v1.50.0&method=post", "POST /v1/{project_id}/vpcs"},
+	}
+	for _, c := range cases {
+		var ops []Operation
+		serve(t, mux, http.MethodGet, "/api/operations?"+c.query, &ops)
+		if got := operationKeys(ops); got != c.want {
+			t.Errorf("operations of %s = %s, want %s", c.query, got, c.want)
+		}
+	}
+
+	var resources []resourceSummary
+	serve(t, mux, http.MethodGet, "/api/resources?product=ECS", &resources)
+	if len(resources) != 1 || resources[0].Name != "resource_huaweicloud_compute_instance" {
+		t.Errorf("unexpected resources of ECS: %+v", resources)
+	}
+}
+
+func TestHandleOperationsEscapeHTML(t *testing.T) {
+	mux := newTestServeMux(t)
+
+	w := serve(t, mux, http.MethodGet, "/api/resources/resource_huaweicloud_compute_instance", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", w.Code, w.Body)
+	}
+	body := w.Body.String()
+	if strings.Contains(body, "<script>") || strings.Contains(body, "<b>") {
+		t.Errorf("the HTML in the operation is not escaped: %s", body)
+	}
+
+	// 转义后的内容可以还原
+	var rs Resource
+	if err := json.Unmarshal(w.Body.Bytes(), &rs); err != nil {
+		t.Fatal(err)
+	}
+	if rs.Operations[0].OperationId != "ecs.v1.cloudservers.Create<script>alert(1)</script>" {
+		t.Errorf("unexpected operation ID: %s", rs.Operations[0].OperationId)
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	mux := newTestServeMux(t)
+
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodDelete} {
+		w := serve(t, mux, method, "/api/operations", nil)
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Errorf("%s returns %d, want 405", method, w.Code)
+		}
+		if allow := w.Header().Get("Allow"); allow != "GET, HEAD" {
+			t.Errorf("unexpected Allow header of %s: %q", method, allow)
+		}
+	}
+
+	if w := serve(t, mux, http.MethodHead, "/api/versions", nil); w.Code != http.StatusOK {
+		t.Errorf("HEAD returns %d, want 200", w.Code)
+	}
+}
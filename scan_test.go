@@ -0,0 +1,393 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+)
+
+// go test -run TestScanGolden . -update 更新 testdata/golden 中的期望结果
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata/golden")
+
+const (
+	fixtureBasePath = "testdata/provider/"
+	goldenDir       = "testdata/golden"
+)
+
+// 测试使用的 ServiceCatalog, 不依赖provider中的 endpoints.go
+var fixtureServiceCatalogs = map[string]config.ServiceCatalog{
+	"cce":       {Name: "cce", Version: "api/v3/projects", Product: "CCE"},
+	"ecs":       {Name: "ecs", Version: "v1", Product: "ECS"},
+	"vpc":       {Name: "vpc", Version: "v1", WithOutProjectID: true, Product: "VPC"},
+	"networkv2": {Name: "vpc", Version: "v2.0", WithOutProjectID: true, Product: "VPC"},
+	"evsv1":     {Name: "evs", Version: "v1", Product: "EVS"},
+}
+
+// fixture provider 中导出的 resource 和 data source
+var (
+	fixtureResources = []string{
+		"huaweicloud_cce_node",
+		"huaweicloud_compute_instance",
+		"huaweicloud_compute_volume_attach",
+		"huaweicloud_vpc",
+		"huaweicloud_vpc_subnet",
+		"huaweicloud_vpc_bandwidth",
+		"huaweicloud_rds_backup",
+		"huaweicloud_evs_volume",
+	}
+	fixtureDataSources = []string{
+		"huaweicloud_compute_flavors",
+		"huaweicloud_vpcs",
+	}
+)
+
+// setupFixtureScan 将全局变量指向 fixture provider, 返回恢复函数
+func setupFixtureScan(t *testing.T) func() {
+	t.Helper()
+
+	oldBasePath, oldOutputDir, oldVersion := basePath, outputDir, version
+	oldProvider, oldFilterFilePath := provider, filterFilePath
+	oldClientDeclInConfig, oldClientConfig, oldClientPackageConfig := clientDeclInConfig, clientConfig, clientPackageConfig
+	oldUrlSupportsInRequestFile := urlSupportsInRequestFile
+	oldUrlEvaluators, oldSdkPackageNames, oldHcModelStructs := urlEvaluators, sdkPackageNames, hcModelStructs
+	oldRecorder, oldGetServiceCatalog, oldLogOut := recorder, getServiceCatalog, logger.out
+	oldWithProvenance, oldRiskRulesPath, oldRiskRules := withProvenance, riskRulesPath, riskRules
+
+	basePath = fixtureBasePath
+	outputDir = t.TempDir() + "/"
+	version = "v0.0.1"
+	provider = "huaweicloud"
+	filterFilePath = ""
+	withProvenance = false
+	riskRulesPath = "config/risk_rules.json"
+	riskRules = nil
+	clientDeclInConfig = make(map[string]string)
+	clientConfig = make(map[string]string)
+	clientPackageConfig = make(map[string]string)
+	urlSupportsInRequestFile = make(map[string][]CloudUri)
+	urlEvaluators = make(map[string]*urlEvaluator)
+	sdkPackageNames = make(map[string]string)
+	hcModelStructs = make(map[string]map[string]*ast.StructType)
+	recorder = &scanRecorder{resources: make(map[string]*resourceReport)}
+	getServiceCatalog = func(name string) *config.ServiceCatalog {
+		if catalog, ok := fixtureServiceCatalogs[name]; ok {
+			return &catalog
+		}
+		return nil
+	}
+	logger.out = io.Discard
+
+	return func() {
+		basePath, outputDir, version = oldBasePath, oldOutputDir, oldVersion
+		provider, filterFilePath = oldProvider, oldFilterFilePath
+		clientDeclInConfig, clientConfig, clientPackageConfig = oldClientDeclInConfig, oldClientConfig, oldClientPackageConfig
+		urlSupportsInRequestFile = oldUrlSupportsInRequestFile
+		urlEvaluators, sdkPackageNames, hcModelStructs = oldUrlEvaluators, oldSdkPackageNames, oldHcModelStructs
+		recorder, getServiceCatalog, logger.out = oldRecorder, oldGetServiceCatalog, oldLogOut
+		withProvenance, riskRulesPath, riskRules = oldWithProvenance, oldRiskRulesPath, oldRiskRules
+	}
+}
+
+// runFixtureScan 扫描 fixture provider 并返回输出目录
+func runFixtureScan(t *testing.T) string {
+	t.Helper()
+
+	if err := parseConfigFile(basePath + "huaweicloud/config/config.go"); err != nil {
+		t.Fatalf("failed to parse the config file: %s", err)
+	}
+	if err := parseHCConfigFile(basePath + "huaweicloud/config/hc_config.go"); err != nil {
+		t.Fatalf("failed to parse the HC config file: %s", err)
+	}
+	if err := loadRiskRules(riskRulesPath); err != nil {
+		t.Fatalf("failed to load the risk rules: %s", err)
+	}
+	if err := scanProvider(fixtureResources, fixtureDataSources); err != nil {
+		t.Fatalf("failed to scan the fixture provider: %s", err)
+	}
+	return outputDir
+}
+
+func listYamlFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		t.Fatalf("failed to list the yaml files in %s: %s", dir, err)
+	}
+
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		names = append(names, filepath.Base(f))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestScanGolden(t *testing.T) {
+	defer setupFixtureScan(t)()
+	dir := runFixtureScan(t)
+
+	got := listYamlFiles(t, dir)
+	if *updateGolden {
+		if err := os.RemoveAll(goldenDir); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(goldenDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range got {
+			content, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(goldenDir, name), content, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		t.Logf("updated %d golden files in %s", len(got), goldenDir)
+		return
+	}
+
+	want := listYamlFiles(t, goldenDir)
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected API files:\n got: %v\nwant: %v", got, want)
+	}
+
+	for _, name := range want {
+		expected, err := os.ReadFile(filepath.Join(goldenDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(actual) != string(expected) {
+			t.Errorf("%s does not match the golden file, run `go test -run TestScanGolden . -update` "+
+				"if the change is expected\n--- got ---\n%s\n--- want ---\n%s", name, actual, expected)
+		}
+	}
+}
+
+// 两次扫描相同的源码, 输出的文件(包括 skip_files.txt 和报告)完全相同
+func TestScanReproducible(t *testing.T) {
+	scan := func() map[string]string {
+		defer setupFixtureScan(t)()
+		dir := runFixtureScan(t)
+
+		report := recorder.buildReport(version)
+		if err := writeCoverageReport(dir, report); err != nil {
+			t.Fatal(err)
+		}
+		if err := writeDependencyReport(dir, buildDependencyReport(report)); err != nil {
+			t.Fatal(err)
+		}
+
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents := make(map[string]string)
+		for _, f := range files {
+			content, err := os.ReadFile(filepath.Join(dir, f.Name()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			contents[f.Name()] = string(content)
+		}
+		return contents
+	}
+
+	first, second := scan(), scan()
+	if len(first) != len(second) {
+		t.Fatalf("the number of output files differs: %d != %d", len(first), len(second))
+	}
+	for name, content := range first {
+		if second[name] != content {
+			t.Errorf("%s differs between two scans\n--- first ---\n%s\n--- second ---\n%s", name, content, second[name])
+		}
+	}
+	if _, ok := first["skip_files.txt"]; !ok {
+		t.Errorf("skip_files.txt is not written")
+	}
+}
+
+func TestScanReport(t *testing.T) {
+	defer setupFixtureScan(t)()
+	runFixtureScan(t)
+
+	report := recorder.buildReport(version)
+	names := []string{}
+	for _, rs := range report.Resources {
+		names = append(names, rs.Name)
+		if len(rs.Operations) == 0 {
+			t.Errorf("%s has no API", rs.Name)
+		}
+	}
+
+	// 自动生成的资源不扫描, 语法错误的资源被跳过
+	want := []string{
+		"data_source_huaweicloud_compute_flavors",
+		"data_source_huaweicloud_vpcs",
+		"resource_huaweicloud_cce_node",
+		"resource_huaweicloud_compute_instance",
+		"resource_huaweicloud_compute_volume_attach",
+		"resource_huaweicloud_vpc",
+		"resource_huaweicloud_vpc_bandwidth",
+		"resource_huaweicloud_vpc_subnet",
+	}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected resources in the report:\n got: %v\nwant: %v", names, want)
+	}
+	for _, rs := range report.Resources {
+		// 创建、变更规格、开关机、删除和更新标签
+		if rs.Name == "resource_huaweicloud_compute_instance" && rs.MutatingOperations != 6 {
+			t.Errorf("expect 6 mutating APIs of %s, got %d", rs.Name, rs.MutatingOperations)
+		}
+		// 删除重试和查询创建任务的状态
+		if rs.Name == "resource_huaweicloud_compute_instance" && rs.PollingOperations != 2 {
+			t.Errorf("expect 2 polling APIs of %s, got %d", rs.Name, rs.PollingOperations)
+		}
+		// ECS 和 EVS 的 client 构造相同的任务路径, 只保留一个请求
+		if rs.Name == "resource_huaweicloud_compute_volume_attach" {
+			jobs := []string{}
+			for _, op := range rs.Operations {
+				if op.Path == "/v1/{project_id}/jobs/{job_id}" {
+					jobs = append(jobs, op.Method+" "+op.OperationId)
+				}
+			}
+			if len(jobs) != 1 {
+				t.Errorf("expect one job API of %s, got %v", rs.Name, jobs)
+			}
+		}
+		// v1 和 v2.0 的 client 构造相同的相对路径
+		if rs.Name == "resource_huaweicloud_vpc_bandwidth" {
+			gets := []string{}
+			for _, op := range rs.Operations {
+				if op.Method == "get" {
+					gets = append(gets, op.OperationId+" "+op.Path)
+				}
+			}
+			want := "networking.v1.bandwidths.Get /v1/{project_id}/bandwidths/{id}," +
+				"networking.v2.bandwidths.Get /v2.0/{project_id}/bandwidths/{id}"
+			if strings.Join(gets, ",") != want {
+				t.Errorf("unexpected GET APIs of %s: %v", rs.Name, gets)
+			}
+		}
+	}
+	// SDK包中的类型转换和没有绑定SDK的 client.Request 不是未解析的调用
+	if report.Summary.UnresolvedCalls != 0 {
+		t.Errorf("expect no unresolved calls, got %v", report.UnresolvedCalls)
+	}
+
+	if len(report.Errors) != 1 || report.Summary.Errors != 1 {
+		t.Fatalf("expect one error of the broken package, got %v", report.Errors)
+	}
+	if e := report.Errors[0]; e.Stage != stagePackage || !strings.Contains(e.File, "services/evs") {
+		t.Errorf("unexpected error: %v", e)
+	}
+}
+
+func TestDependencyReport(t *testing.T) {
+	defer setupFixtureScan(t)()
+	runFixtureScan(t)
+
+	deps := buildDependencyReport(recorder.buildReport(version))
+	for _, rs := range deps.Resources {
+		want := "[]"
+		// 创建云服务器前检查子网
+		if rs.Name == "resource_huaweicloud_compute_instance" {
+			want = "[VPC]"
+		}
+		if got := fmt.Sprint(rs.Dependencies); got != want {
+			t.Errorf("dependencies of %s = %s, want %s", rs.Name, got, want)
+		}
+	}
+	if got := fmt.Sprint(deps.Services); got != "map[VPC:[resource_huaweicloud_compute_instance]]" {
+		t.Errorf("unexpected dependent resources: %s", got)
+	}
+}
+
+func TestScanProvenance(t *testing.T) {
+	defer setupFixtureScan(t)()
+	withProvenance = true
+	dir := runFixtureScan(t)
+
+	cases := map[string][]string{
+		"resource_huaweicloud_compute_instance.yaml": {`
+      x-action: os-stop
+      x-category: action
+      x-sdk-package: github.com/chnsz/golangsdk/openstack/ecs/v1/cloudservers
+      x-sdk-function: Stop
+      x-terraform-function: resourceComputeInstanceUpdate
+      x-source: huaweicloud/services/ecs/resource_huaweicloud_compute_instance.go:140
+`},
+		// 链式调用的位置为创建client的调用
+		"data_source_huaweicloud_vpcs.yaml": {`
+      x-sdk-package: github.com/huaweicloud/huaweicloud-sdk-go-v3/services/vpc/v3
+      x-sdk-function: ListVpcsInvoker
+      x-terraform-function: dataSourceVpcsRead
+      x-source: huaweicloud/services/vpc/data_source_huaweicloud_vpcs.go:45
+`},
+	}
+	for name, wants := range cases {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s does not contain the provenance:%s\n--- got ---\n%s", name, want, content)
+			}
+		}
+	}
+}
+
+func TestScanMissingConfig(t *testing.T) {
+	defer setupFixtureScan(t)()
+
+	if err := parseConfigFile(basePath + "huaweicloud/config/not_exist.go"); err == nil {
+		t.Error("expect an error when the config file does not exist")
+	}
+	if err := parseHCConfigFile(basePath + "huaweicloud/config/not_exist.go"); err == nil {
+		t.Error("expect an error when the HC config file does not exist")
+	}
+}
+
+func TestIsAutoGenetatedFile(t *testing.T) {
+	cases := map[string]bool{
+		fixtureBasePath + "huaweicloud/services/rds/resource_huaweicloud_rds_backup.go":       true,
+		fixtureBasePath + "huaweicloud/services/vpc/resource_huaweicloud_vpc.go":              false,
+		fixtureBasePath + "huaweicloud/services/ecs/resource_huaweicloud_compute_instance.go": false,
+	}
+
+	// 文件长度小于检查的长度
+	shortFile := filepath.Join(t.TempDir(), "resource_huaweicloud_short.go")
+	if err := os.WriteFile(shortFile, []byte("package short\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cases[shortFile] = false
+
+	for path, want := range cases {
+		got, err := isAutoGenetatedFile(path)
+		if err != nil {
+			t.Errorf("isAutoGenetatedFile(%s) returns an error: %s", path, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("isAutoGenetatedFile(%s) = %v, want %v", path, got, want)
+		}
+	}
+
+	if _, err := isAutoGenetatedFile(fixtureBasePath + "not_exist.go"); err == nil {
+		t.Error("expect an error when the file does not exist")
+	}
+}
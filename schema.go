@@ -0,0 +1,503 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maxSchemaDepth 嵌套结构体的最大深度
+const maxSchemaDepth = 8
+
+// jsonSchema 请求体或者响应体的JSON schema
+type jsonSchema struct {
+	typ        string
+	properties []schemaProperty // 按照字段的定义顺序
+	required   []string
+	items      *jsonSchema // typ 为 array 时元素的schema
+}
+
+type schemaProperty struct {
+	name   string
+	schema *jsonSchema
+}
+
+// schemaBuilder 根据SDK中的结构体生成JSON schema
+type schemaBuilder struct {
+	// structs 查找SDK包中定义的结构体
+	structs func(name string) *ast.StructType
+	// isRequired 判断字段是否必选, golangsdk 使用 required 标签, huaweicloud-sdk-go-v3 使用非指针且没有 omitempty
+	isRequired func(field *ast.Field, tag reflect.StructTag) bool
+	visiting   map[string]bool
+}
+
+func newSchemaBuilder(structs func(name string) *ast.StructType,
+	isRequired func(field *ast.Field, tag reflect.StructTag) bool) *schemaBuilder {
+	return &schemaBuilder{structs: structs, isRequired: isRequired, visiting: make(map[string]bool)}
+}
+
+// golangsdk 的结构体使用 required:"true" 标记必选字段
+func isGolangsdkFieldRequired(_ *ast.Field, tag reflect.StructTag) bool {
+	return tag.Get("required") == "true"
+}
+
+// huaweicloud-sdk-go-v3 的必选字段不是指针, 也没有 omitempty
+func isHcFieldRequired(field *ast.Field, tag reflect.StructTag) bool {
+	_, isPointer := field.Type.(*ast.StarExpr)
+	return !isPointer && !strings.Contains(tag.Get("json"), "omitempty")
+}
+
+// schemaOfType 生成类型的schema, 其他包中的类型作为没有属性的 object 处理, time.Time 等作为 string 处理
+func (b *schemaBuilder) schemaOfType(expr ast.Expr, depth int) *jsonSchema {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return b.schemaOfType(t.X, depth)
+	case *ast.ArrayType:
+		return &jsonSchema{typ: "array", items: b.schemaOfType(t.Elt, depth)}
+	case *ast.MapType, *ast.InterfaceType:
+		return &jsonSchema{typ: "object"}
+	case *ast.SelectorExpr:
+		if t.Sel.Name == "Time" || t.Sel.Name == "SdkTime" {
+			return &jsonSchema{typ: "string"}
+		}
+		return &jsonSchema{typ: "object"}
+	case *ast.StructType:
+		return b.schemaOfStruct(t, depth)
+	case *ast.Ident:
+		if typ, _ := swaggerType(t); typ != "string" || t.Name == "string" {
+			return &jsonSchema{typ: typ}
+		}
+
+		st := b.structs(t.Name)
+		if st == nil || b.visiting[t.Name] || depth >= maxSchemaDepth {
+			return &jsonSchema{typ: "object"}
+		}
+		// huaweicloud-sdk-go-v3 的枚举类型, eg: type XxxStatus struct { value string }
+		if isEnumStruct(st) {
+			return &jsonSchema{typ: "string"}
+		}
+
+		b.visiting[t.Name] = true
+		defer delete(b.visiting, t.Name)
+		return b.schemaOfStruct(st, depth+1)
+	}
+	return &jsonSchema{typ: "string"}
+}
+
+func (b *schemaBuilder) schemaOfStruct(st *ast.StructType, depth int) *jsonSchema {
+	s := &jsonSchema{typ: "object"}
+	for _, field := range st.Fields.List {
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			tag = reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
+		}
+		jsonName := strings.Split(tag.Get("json"), ",")[0]
+		if jsonName == "-" {
+			continue
+		}
+
+		// 没有json名称的匿名字段, 属性合并到当前结构体中
+		if len(field.Names) == 0 {
+			embedded := b.schemaOfType(field.Type, depth)
+			if jsonName == "" {
+				s.properties = append(s.properties, embedded.properties...)
+				s.required = append(s.required, embedded.required...)
+				continue
+			}
+			s.properties = append(s.properties, schemaProperty{name: jsonName, schema: embedded})
+			if b.isRequired(field, tag) {
+				s.required = append(s.required, jsonName)
+			}
+			continue
+		}
+
+		for _, name := range field.Names {
+			if !ast.IsExported(name.Name) {
+				continue
+			}
+			propName := jsonName
+			if propName == "" {
+				propName = name.Name
+			}
+			s.properties = append(s.properties, schemaProperty{name: propName, schema: b.schemaOfType(field.Type, depth)})
+			if b.isRequired(field, tag) {
+				s.required = append(s.required, propName)
+			}
+		}
+	}
+	return s
+}
+
+// isEnumStruct 判断是否是只包含未导出字段的枚举结构体
+func isEnumStruct(st *ast.StructType) bool {
+	if len(st.Fields.List) == 0 {
+		return false
+	}
+	for _, field := range st.Fields.List {
+		for _, name := range field.Names {
+			if ast.IsExported(name.Name) {
+				return false
+			}
+		}
+		if len(field.Names) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// renderSchema 生成schema的YAML, indent 为schema中第一层属性的缩进
+func renderSchema(s *jsonSchema, indent int) string {
+	prefix := strings.Repeat(" ", indent)
+	rst := fmt.Sprintf("\n%stype: %s", prefix, s.typ)
+
+	if len(s.required) > 0 {
+		rst += fmt.Sprintf("\n%srequired:", prefix)
+		for _, name := range s.required {
+			rst += fmt.Sprintf("\n%s  - %s", prefix, name)
+		}
+	}
+	if len(s.properties) > 0 {
+		rst += fmt.Sprintf("\n%sproperties:", prefix)
+		for _, p := range s.properties {
+			rst += fmt.Sprintf("\n%s  %s:", prefix, p.name) + renderSchema(p.schema, indent+4)
+		}
+	}
+	if s.items != nil {
+		rst += fmt.Sprintf("\n%sitems:", prefix) + renderSchema(s.items, indent+2)
+	}
+	return rst
+}
+
+var buildRequestBodyReg = regexp.MustCompile(`BuildRequestBody\(\w+,\s*"(\w*)"\)`)
+
+// requestBodySchema 根据函数参数中 opts 的类型生成请求体的schema
+// opts 的类型或者实现的接口方法中通过 golangsdk.BuildRequestBody(opts, "server") 构造请求体, 第二个参数不为空时作为根节点
+func (e *urlEvaluator) requestBodySchema(fn *ast.FuncDecl) *jsonSchema {
+	builder := newSchemaBuilder(e.structType, isGolangsdkFieldRequired)
+	for _, field := range fn.Type.Params.List {
+		for _, structName := range e.optsStructs(localTypeName(field.Type)) {
+			for _, m := range e.methods[structName] {
+				match := buildRequestBodyReg.FindStringSubmatch(e.funcSource(m))
+				if len(match) < 2 {
+					continue
+				}
+
+				body := builder.schemaOfType(ast.NewIdent(structName), 0)
+				if parent := match[1]; parent != "" {
+					body = &jsonSchema{
+						typ:        "object",
+						properties: []schemaProperty{{name: parent, schema: body}},
+						required:   []string{parent},
+					}
+				}
+				return body
+			}
+		}
+	}
+	return nil
+}
+
+// bodyActionKey 返回只有一个根节点的请求体的根节点名称, eg: {"os-stop": {...}} -> os-stop
+func bodyActionKey(body *jsonSchema) string {
+	if body == nil || len(body.properties) != 1 {
+		return ""
+	}
+	return body.properties[0].name
+}
+
+// actionKey 返回函数构造的请求体的根节点名称, 用于区分 .../action 请求
+// 请求体通过 BuildRequestBody(opts, "resize") 构造, 或者使用只有一个键的map, eg: map[string]interface{}{"os-stop": nil}
+// 也可以由本包的辅助函数返回只有一个键的map, eg: actionMap("add", groupName) 返回 {prefix + "SecurityGroup": ...}
+func (e *urlEvaluator) actionKey(fn *ast.FuncDecl, requestBody *jsonSchema) string {
+	if key := bodyActionKey(requestBody); key != "" || fn.Body == nil {
+		return key
+	}
+
+	var key string
+	scope := e.funcScope(fn, nil, 0)
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if key != "" {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.CompositeLit:
+			if elt := singleMapElement(node); elt != nil {
+				key = e.mapKey(elt, scope)
+				return false
+			}
+		case *ast.CallExpr:
+			key = e.helperActionKey(node, scope)
+		}
+		return key == ""
+	})
+	return key
+}
+
+// helperActionKey 计算本包辅助函数返回的只有一个键的map的键, 参数使用调用时的值
+func (e *urlEvaluator) helperActionKey(call *ast.CallExpr, scope *evalScope) string {
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	helper, ok := e.funcs[ident.Name]
+	if !ok || helper.Body == nil {
+		return ""
+	}
+	args, err := e.evalArgs(call, scope)
+	if err != nil {
+		return ""
+	}
+
+	helperScope := e.funcScope(helper, args, scope.depth+1)
+	for _, stmt := range helper.Body.List {
+		ret, ok := stmt.(*ast.ReturnStmt)
+		if !ok || len(ret.Results) == 0 {
+			continue
+		}
+		if lit, ok := ret.Results[0].(*ast.CompositeLit); ok {
+			if elt := singleMapElement(lit); elt != nil {
+				return e.mapKey(elt, helperScope)
+			}
+		}
+	}
+	return ""
+}
+
+// singleMapElement 返回只有一个元素的map字面量的元素
+func singleMapElement(lit *ast.CompositeLit) *ast.KeyValueExpr {
+	if _, ok := lit.Type.(*ast.MapType); !ok || len(lit.Elts) != 1 {
+		return nil
+	}
+	kv, _ := lit.Elts[0].(*ast.KeyValueExpr)
+	return kv
+}
+
+// mapKey 计算map元素的键, 包含参数等占位符时无法确定操作, 返回空字符串
+func (e *urlEvaluator) mapKey(kv *ast.KeyValueExpr, scope *evalScope) string {
+	key, err := e.eval(kv.Key, scope)
+	if err != nil || strings.ContainsAny(key, "{}") {
+		return ""
+	}
+	return key
+}
+
+// structType 查找包中定义的结构体
+func (e *urlEvaluator) structType(name string) *ast.StructType {
+	if ts, ok := e.types[name]; ok {
+		if st, ok := ts.Type.(*ast.StructType); ok {
+			return st
+		}
+	}
+	return nil
+}
+
+// hcRequestBodySchema 根据 XxxRequest 中 Body 字段的类型生成请求体的schema, eg: Body *CreateVpcRequestBody
+func hcRequestBodySchema(sdkFileDir string, request *ast.StructType) *jsonSchema {
+	field := findStructField(request, "Body")
+	if field == nil {
+		return nil
+	}
+
+	structs := getHcModelStructs(sdkFileDir)
+	builder := newSchemaBuilder(func(name string) *ast.StructType {
+		return structs[name]
+	}, isHcFieldRequired)
+	return builder.schemaOfType(field.Type, 0)
+}
+
+// responseBodySchema 根据函数返回的 XxxResult 中 Extract 方法解析的结构体生成响应体的schema
+// 返回 pagination.Pager 时, 使用解析对应 XxxPage 的 ExtractXxx 函数
+func (e *urlEvaluator) responseBodySchema(fn *ast.FuncDecl) *jsonSchema {
+	if fn.Type.Results == nil || len(fn.Type.Results.List) == 0 {
+		return nil
+	}
+
+	var extractFuncs []*ast.FuncDecl
+	if resultType := localTypeName(fn.Type.Results.List[0].Type); resultType != "" {
+		extractFuncs = e.extractMethods(resultType)
+	} else if match := pageTypeReg.FindStringSubmatch(e.funcSource(fn)); len(match) > 1 {
+		extractFuncs = e.extractPageFuncs(match[1])
+	}
+
+	builder := newSchemaBuilder(e.structType, isGolangsdkFieldRequired)
+	for _, extractFunc := range extractFuncs {
+		if body := extractedSchema(extractFunc, builder); body != nil {
+			return body
+		}
+	}
+	return nil
+}
+
+// eg: return FlavorPage{pagination.LinkedPageBase{PageResult: r}}
+var pageTypeReg = regexp.MustCompile(`return\s+(\w+Page)\{`)
+
+// extractMethods 返回 XxxResult 及其匿名字段(eg: commonResult)的 Extract 方法, Extract 排在最前面
+func (e *urlEvaluator) extractMethods(typeName string) []*ast.FuncDecl {
+	methods := []*ast.FuncDecl{}
+	for _, m := range e.methods[typeName] {
+		name := m.Name.Name
+		if strings.HasPrefix(name, "Extract") && name != "ExtractErr" && !strings.HasPrefix(name, "ExtractInto") {
+			methods = append(methods, m)
+		}
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		if (methods[i].Name.Name == "Extract") != (methods[j].Name.Name == "Extract") {
+			return methods[i].Name.Name == "Extract"
+		}
+		return methods[i].Name.Name < methods[j].Name.Name
+	})
+
+	if st := e.structType(typeName); st != nil {
+		for _, field := range st.Fields.List {
+			if embedded := localTypeName(field.Type); len(field.Names) == 0 && embedded != "" && embedded != typeName {
+				methods = append(methods, e.extractMethods(embedded)...)
+			}
+		}
+	}
+	return methods
+}
+
+// extractPageFuncs 返回从 XxxPage 中解析数据的函数, eg: ExtractFlavors(r pagination.Page)
+func (e *urlEvaluator) extractPageFuncs(pageType string) []*ast.FuncDecl {
+	funcs := []*ast.FuncDecl{}
+	for _, fn := range e.allFuncs() {
+		if fn.Recv == nil && strings.HasPrefix(fn.Name.Name, "Extract") &&
+			strings.Contains(e.funcSource(fn), ".("+pageType+")") {
+			funcs = append(funcs, fn)
+		}
+	}
+	return funcs
+}
+
+// extractedSchema 根据 ExtractInto 等方法的参数类型生成schema
+// eg: var s struct{...}; r.ExtractInto(&s) 或者 job := new(JobResponse); r.ExtractInto(job)
+// ExtractIntoStructPtr(&s, "server") 的第二个参数作为根节点
+func extractedSchema(fn *ast.FuncDecl, builder *schemaBuilder) *jsonSchema {
+	if fn.Body == nil {
+		return nil
+	}
+
+	var varName, label string
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || varName != "" {
+			return varName == ""
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !strings.HasPrefix(sel.Sel.Name, "ExtractInto") || len(call.Args) == 0 {
+			return true
+		}
+
+		arg := call.Args[0]
+		if unary, ok := arg.(*ast.UnaryExpr); ok {
+			arg = unary.X
+		}
+		if ident, ok := arg.(*ast.Ident); ok {
+			varName = ident.Name
+		}
+		if len(call.Args) > 1 {
+			if lit, ok := call.Args[1].(*ast.BasicLit); ok {
+				label = strings.Trim(lit.Value, `"`)
+			}
+		}
+		return false
+	})
+	if varName == "" {
+		return nil
+	}
+
+	varType := localVarType(fn.Body, varName)
+	if varType == nil {
+		return nil
+	}
+	body := builder.schemaOfType(varType, 0)
+	if label != "" {
+		body = &jsonSchema{typ: "object", properties: []schemaProperty{{name: label, schema: body}}}
+	}
+	return body
+}
+
+// localVarType 查找函数中局部变量的类型, 支持 var s T, s := new(T), s := &T{}, s := T{}
+func localVarType(body *ast.BlockStmt, varName string) ast.Expr {
+	var varType ast.Expr
+	ast.Inspect(body, func(n ast.Node) bool {
+		if varType != nil {
+			return false
+		}
+		switch s := n.(type) {
+		case *ast.ValueSpec:
+			for _, name := range s.Names {
+				if name.Name == varName && s.Type != nil {
+					varType = s.Type
+				}
+			}
+		case *ast.AssignStmt:
+			for i, lhs := range s.Lhs {
+				if ident, ok := lhs.(*ast.Ident); !ok || ident.Name != varName || i >= len(s.Rhs) {
+					continue
+				}
+				varType = typeOfValue(s.Rhs[i])
+			}
+		}
+		return true
+	})
+	return varType
+}
+
+// typeOfValue 返回 new(T), &T{}, T{} 的类型 T
+func typeOfValue(expr ast.Expr) ast.Expr {
+	switch v := expr.(type) {
+	case *ast.CallExpr:
+		if ident, ok := v.Fun.(*ast.Ident); ok && ident.Name == "new" && len(v.Args) == 1 {
+			return v.Args[0]
+		}
+	case *ast.UnaryExpr:
+		return typeOfValue(v.X)
+	case *ast.CompositeLit:
+		return v.Type
+	}
+	return nil
+}
+
+// eg: WithResponse(new(model.ShowVpcResponse))
+var hcResponseReg = regexp.MustCompile(`WithResponse\(new\(\w+\.(\w+)\)\)`)
+
+// hcResponseBodySchema 根据 XxxResponse 生成响应体的schema, 通过 WithResponseField 定义在header中的字段不属于响应体
+func hcResponseBodySchema(sdkFileDir string, funcSrc string) *jsonSchema {
+	match := hcResponseReg.FindStringSubmatch(funcSrc)
+	if len(match) < 2 {
+		return nil
+	}
+	structs := getHcModelStructs(sdkFileDir)
+	response := structs[match[1]]
+	if response == nil {
+		return nil
+	}
+
+	headers := []string{}
+	for _, p := range parseHcFieldDefs(funcSrc, "WithResponseField(") {
+		if p.location == "Header" {
+			headers = append(headers, p.name)
+		}
+	}
+	fields := []*ast.Field{}
+	for _, field := range response.Fields.List {
+		if len(field.Names) == 1 && sliceContains(headers, field.Names[0].Name) {
+			continue
+		}
+		fields = append(fields, field)
+	}
+
+	builder := newSchemaBuilder(func(name string) *ast.StructType {
+		return structs[name]
+	}, isHcFieldRequired)
+	body := builder.schemaOfStruct(&ast.StructType{Fields: &ast.FieldList{List: fields}}, 0)
+	if len(body.properties) == 0 {
+		return nil
+	}
+	return body
+}
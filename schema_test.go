@@ -0,0 +1,93 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRequestBodySchema(t *testing.T) {
+	defer setupFixtureScan(t)()
+
+	evaluator, err := getURLEvaluator(basePath + "vendor/" + golangsdkPrefix + "ecs/v1/cloudservers")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create(client, opts CreateOptsBuilder) 使用 BuildRequestBody(opts, "server") 构造请求体
+	body := evaluator.requestBodySchema(evaluator.funcs["Create"])
+	if body == nil {
+		t.Fatal("the request body of Create is not found")
+	}
+	want := `
+type: object
+required:
+  - server
+properties:
+  server:
+    type: object
+    required:
+      - imageRef
+      - flavorRef
+      - name
+    properties:
+      imageRef:
+        type: string
+      flavorRef:
+        type: string
+      name:
+        type: string`
+	if got := renderSchema(body, 0); got != want {
+		t.Errorf("unexpected request body schema:\n got: %s\nwant: %s", got, want)
+	}
+
+	// 没有 opts 参数的请求没有请求体
+	if body := evaluator.requestBodySchema(evaluator.funcs["Get"]); body != nil {
+		t.Errorf("unexpected request body of Get: %s", strings.TrimSpace(renderSchema(body, 0)))
+	}
+}
+
+func TestResponseBodySchema(t *testing.T) {
+	defer setupFixtureScan(t)()
+
+	cases := []struct {
+		sdkPackage string
+		funcName   string
+		want       string
+	}{
+		// GetResult.Extract() 中通过 ExtractInto 解析 server
+		{"ecs/v1/cloudservers", "Get", "object{server:object{id:string,name:string,status:string}}"},
+		// JobResult.ExtractJobResponse() 中通过 new(JobResponse) 定义变量
+		{"ecs/v1/cloudservers", "Create", "object{job_id:string}"},
+		// List 返回 FlavorPage, 使用 ExtractFlavors 解析
+		{"ecs/v1/flavors", "List", "object{flavors:array[object{id:string,name:string,vcpus:string,ram:integer}]}"},
+		// Extract 方法定义在匿名字段 commonResult 中
+		{"cce/v3/nodes", "Get", "object{kind:string,apiVersion:string,metadata:object{name:string,uid:string}}"},
+	}
+	for _, c := range cases {
+		evaluator, err := getURLEvaluator(basePath + "vendor/" + golangsdkPrefix + c.sdkPackage)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := schemaString(evaluator.responseBodySchema(evaluator.funcs[c.funcName])); got != c.want {
+			t.Errorf("unexpected response body of %s.%s:\n got: %s\nwant: %s", c.sdkPackage, c.funcName, got, c.want)
+		}
+	}
+}
+
+// schemaString 生成schema的简写, 便于比较
+func schemaString(s *jsonSchema) string {
+	if s == nil {
+		return "nil"
+	}
+	switch {
+	case s.items != nil:
+		return s.typ + "[" + schemaString(s.items) + "]"
+	case len(s.properties) > 0:
+		props := []string{}
+		for _, p := range s.properties {
+			props = append(props, p.name+":"+schemaString(p.schema))
+		}
+		return s.typ + "{" + strings.Join(props, ",") + "}"
+	}
+	return s.typ
+}
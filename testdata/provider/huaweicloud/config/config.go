@@ -0,0 +1,38 @@
+package config
+
+import (
+	"github.com/chnsz/golangsdk"
+)
+
+type Config struct {
+	Region    string
+	ProjectID string
+}
+
+func (c *Config) NewServiceClient(srv, region string) (*golangsdk.ServiceClient, error) {
+	return &golangsdk.ServiceClient{}, nil
+}
+
+func (c *Config) GetRegion(d interface{}) string {
+	return c.Region
+}
+
+func (c *Config) ComputeV1Client(region string) (*golangsdk.ServiceClient, error) {
+	return c.NewServiceClient("ecs", region)
+}
+
+func (c *Config) NetworkingV1Client(region string) (*golangsdk.ServiceClient, error) {
+	return c.NewServiceClient("vpc", region)
+}
+
+func (c *Config) NetworkingV2Client(region string) (*golangsdk.ServiceClient, error) {
+	return c.NewServiceClient("networkv2", region)
+}
+
+func (c *Config) CceV3Client(region string) (*golangsdk.ServiceClient, error) {
+	return c.NewServiceClient("cce", region)
+}
+
+func (c *Config) BlockStorageV1Client(region string) (*golangsdk.ServiceClient, error) {
+	return c.NewServiceClient("evsv1", region)
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core"
+	vpcv2 "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/vpc/v2"
+	vpcv3 "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/vpc/v3"
+)
+
+func NewHcClient(c *Config, region, product string, globalFlag bool) (*core.HcHttpClient, error) {
+	return &core.HcHttpClient{}, nil
+}
+
+// HcVpcV3Client is the VPC service client using huaweicloud-sdk-go-v3 package
+func (c *Config) HcVpcV3Client(region string) (*vpcv3.VpcClient, error) {
+	hcClient, err := NewHcClient(c, region, "vpc", false)
+	if err != nil {
+		return nil, err
+	}
+	return vpcv3.NewVpcClient(hcClient), nil
+}
+
+// HcVpcV2Client is the VPC service client using huaweicloud-sdk-go-v3 v2 package
+func (c *Config) HcVpcV2Client(region string) (*vpcv2.VpcClient, error) {
+	hcClient, err := NewHcClient(c, region, "vpc", false)
+	if err != nil {
+		return nil, err
+	}
+	return vpcv2.NewVpcClient(hcClient), nil
+}
@@ -0,0 +1,93 @@
+package cce
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/chnsz/golangsdk/openstack/cce/v3/nodes"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+)
+
+func ResourceNode() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceNodeCreate,
+		ReadContext:   resourceNodeRead,
+		DeleteContext: resourceNodeDelete,
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"cluster_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+	}
+}
+
+func resourceNodeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	nodeClient, err := cfg.CceV3Client(cfg.GetRegion(d))
+	if err != nil {
+		return diag.Errorf("error creating CCE Node client: %s", err)
+	}
+
+	createOpts := nodes.CreateOpts{
+		Kind:       "Node",
+		ApiVersion: "v3",
+		Metadata: nodes.CreateMetaData{
+			Name: d.Get("name").(string),
+		},
+	}
+	clusterId := d.Get("cluster_id").(string)
+	s, err := nodes.Create(nodeClient, clusterId, createOpts).Extract()
+	if err != nil {
+		return diag.Errorf("error creating CCE Node: %s", err)
+	}
+
+	d.SetId(s.Metadata.Id)
+	return resourceNodeRead(ctx, d, meta)
+}
+
+func resourceNodeRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	nodeClient, err := cfg.CceV3Client(cfg.GetRegion(d))
+	if err != nil {
+		return diag.Errorf("error creating CCE Node client: %s", err)
+	}
+
+	clusterId := d.Get("cluster_id").(string)
+	s, err := nodes.Get(nodeClient, clusterId, d.Id()).Extract()
+	if err != nil {
+		return diag.Errorf("error retrieving CCE Node: %s", err)
+	}
+
+	d.Set("name", s.Metadata.Name)
+	return nil
+}
+
+func resourceNodeDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	nodeClient, err := cfg.CceV3Client(cfg.GetRegion(d))
+	if err != nil {
+		return diag.Errorf("error creating CCE Node client: %s", err)
+	}
+
+	clusterId := d.Get("cluster_id").(string)
+	if err := nodes.Delete(nodeClient, clusterId, d.Id()).ExtractErr(); err != nil {
+		return diag.Errorf("error deleting CCE Node: %s", err)
+	}
+
+	d.SetId("")
+	return nil
+}
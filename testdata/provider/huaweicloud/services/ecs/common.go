@@ -0,0 +1,10 @@
+package ecs
+
+import (
+	"github.com/chnsz/golangsdk/openstack/ecs/v1/cloudservers"
+)
+
+// getServerName is used by several resources in this package and is not a resource itself
+func getServerName(server *cloudservers.CloudServer) string {
+	return server.Name
+}
@@ -0,0 +1,64 @@
+package ecs
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/chnsz/golangsdk/openstack/ecs/v1/flavors"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+)
+
+func DataSourceEcsFlavors() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceEcsFlavorsRead,
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"availability_zone": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceEcsFlavorsRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	ecsClient, err := cfg.ComputeV1Client(region)
+	if err != nil {
+		return diag.Errorf("error creating compute client: %s", err)
+	}
+
+	listOpts := &flavors.ListOpts{
+		AvailabilityZone: d.Get("availability_zone").(string),
+	}
+	pages, err := flavors.List(ecsClient, listOpts).AllPages()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	allFlavors, err := flavors.ExtractFlavors(pages)
+	if err != nil {
+		return diag.Errorf("unable to retrieve flavors: %s", err)
+	}
+
+	ids := make([]string, 0, len(allFlavors))
+	for _, flavor := range allFlavors {
+		ids = append(ids, flavor.ID)
+	}
+	d.SetId(region)
+	return diag.FromErr(d.Set("ids", ids))
+}
@@ -0,0 +1,220 @@
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/chnsz/golangsdk/openstack/common/tags"
+	"github.com/chnsz/golangsdk/openstack/ecs/v1/cloudservers"
+	"github.com/chnsz/golangsdk/openstack/networking/v1/subnets"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
+)
+
+func ResourceComputeInstance() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceComputeInstanceCreate,
+		ReadContext:   resourceComputeInstanceRead,
+		UpdateContext: resourceComputeInstanceUpdate,
+		DeleteContext: resourceComputeInstanceDelete,
+
+		Description: "Manages an ECS instance, including its flavor, power state and tags.",
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"image_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"flavor_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"subnet_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"power_action": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tags": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceComputeInstanceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	ecsClient, err := cfg.ComputeV1Client(region)
+	if err != nil {
+		return diag.Errorf("error creating compute client: %s", err)
+	}
+
+	if err := checkServerSubnet(cfg, region, d.Get("subnet_id").(string)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	createOpts := cloudservers.CreateOpts{
+		Name:      d.Get("name").(string),
+		ImageRef:  d.Get("image_id").(string),
+		FlavorRef: d.Get("flavor_id").(string),
+	}
+	n, err := cloudservers.Create(ecsClient, createOpts).ExtractJobResponse()
+	if err != nil {
+		return diag.Errorf("error creating server: %s", err)
+	}
+	if err := waitForServerJobSuccess(ctx, ecsClient, n.JobID, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.Errorf("error waiting for server creation: %s", err)
+	}
+
+	d.SetId(n.JobID)
+	return resourceComputeInstanceRead(ctx, d, meta)
+}
+
+func resourceComputeInstanceRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	ecsClient, err := cfg.ComputeV1Client(region)
+	if err != nil {
+		return diag.Errorf("error creating compute client: %s", err)
+	}
+
+	server, err := cloudservers.Get(ecsClient, d.Id()).Extract()
+	if err != nil {
+		return diag.Errorf("error retrieving server: %s", err)
+	}
+
+	resourceTags, err := tags.Get(ecsClient, "cloudservers", d.Id()).Extract()
+	if err != nil {
+		return diag.Errorf("error fetching server tags: %s", err)
+	}
+
+	mErr := multierror.Append(nil,
+		d.Set("region", region),
+		d.Set("name", server.Name),
+		d.Set("tags", utils.TagsToMap(resourceTags)),
+	)
+	return diag.FromErr(mErr.ErrorOrNil())
+}
+
+func resourceComputeInstanceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	ecsClient, err := cfg.ComputeV1Client(region)
+	if err != nil {
+		return diag.Errorf("error creating compute client: %s", err)
+	}
+
+	if d.HasChange("flavor_id") {
+		resizeOpts := cloudservers.ResizeOpts{
+			FlavorRef: d.Get("flavor_id").(string),
+		}
+		_, err := cloudservers.ResizeAndConfirm(ecsClient, resizeOpts, d.Id()).ExtractJobResponse()
+		if err != nil {
+			return diag.Errorf("error resizing server %s: %s", d.Id(), err)
+		}
+	}
+
+	if d.HasChange("power_action") && d.Get("power_action").(string) == "OFF" {
+		_, err := cloudservers.Stop(ecsClient, d.Id()).ExtractJobResponse()
+		if err != nil {
+			return diag.Errorf("error stopping server %s: %s", d.Id(), err)
+		}
+	}
+
+	if d.HasChange("tags") {
+		tagErr := utils.UpdateResourceTags(ecsClient, d, "cloudservers", d.Id())
+		if tagErr != nil {
+			return diag.Errorf("error updating tags of server %s: %s", d.Id(), tagErr)
+		}
+	}
+	return resourceComputeInstanceRead(ctx, d, meta)
+}
+
+func resourceComputeInstanceDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	ecsClient, err := cfg.ComputeV1Client(region)
+	if err != nil {
+		return diag.Errorf("error creating compute client: %s", err)
+	}
+
+	if err := deleteServer(ecsClient, d.Id()); err != nil {
+		return diag.Errorf("error deleting server: %s", err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func deleteServer(client *golangsdk.ServiceClient, id string) error {
+	// 云服务器正在执行其他任务时删除失败, 需要重试
+	return resource.Retry(5*time.Minute, func() *resource.RetryError {
+		_, err := cloudservers.ForceDelete(client, id).ExtractJobResponse()
+		if err != nil {
+			return resource.RetryableError(err)
+		}
+		return nil
+	})
+}
+
+func waitForServerJobSuccess(ctx context.Context, client *golangsdk.ServiceClient, jobID string,
+	timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"INIT", "RUNNING"},
+		Target:       []string{"SUCCESS"},
+		Refresh:      serverJobRefreshFunc(client, jobID),
+		Timeout:      timeout,
+		Delay:        5 * time.Second,
+		PollInterval: 5 * time.Second,
+	}
+	_, err := stateConf.WaitForStateContext(ctx)
+	return err
+}
+
+func serverJobRefreshFunc(client *golangsdk.ServiceClient, jobID string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		job, err := cloudservers.GetJobResult(client, jobID).ExtractJobStatus()
+		if err != nil {
+			return nil, "ERROR", err
+		}
+		return job, job.Status, nil
+	}
+}
+
+// checkServerSubnet 创建云服务器前检查子网是否存在
+func checkServerSubnet(cfg *config.Config, region, subnetID string) error {
+	if subnetID == "" {
+		return nil
+	}
+
+	vpcClient, err := cfg.NetworkingV1Client(region)
+	if err != nil {
+		return fmt.Errorf("error creating VPC client: %s", err)
+	}
+	if _, err := subnets.Get(vpcClient, subnetID).Extract(); err != nil {
+		return fmt.Errorf("error retrieving subnet %s: %s", subnetID, err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/chnsz/golangsdk/openstack/ecs/v1/block_devices"
+	"github.com/chnsz/golangsdk/openstack/ecs/v1/cloudservers"
+	"github.com/chnsz/golangsdk/openstack/evs/v1/jobs"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+)
+
+func ResourceComputeVolumeAttach() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceComputeVolumeAttachCreate,
+		ReadContext:   resourceComputeVolumeAttachRead,
+		DeleteContext: resourceComputeVolumeAttachDelete,
+
+		Description: "Attaches a volume to an ECS instance.",
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"instance_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"volume_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"device": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceComputeVolumeAttachCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	ecsClient, err := cfg.ComputeV1Client(region)
+	if err != nil {
+		return diag.Errorf("error creating compute client: %s", err)
+	}
+
+	instanceID := d.Get("instance_id").(string)
+	volumeID := d.Get("volume_id").(string)
+	attachOpts := block_devices.AttachOpts{
+		VolumeId: volumeID,
+		Device:   d.Get("device").(string),
+	}
+	job, err := block_devices.Attach(ecsClient, instanceID, attachOpts).ExtractJobResponse()
+	if err != nil {
+		return diag.Errorf("error attaching volume %s to server %s: %s", volumeID, instanceID, err)
+	}
+	// 挂载任务由云服务器的任务接口查询
+	timeout := int(d.Timeout(schema.TimeoutCreate) / time.Second)
+	if err := cloudservers.WaitForJobSuccess(ecsClient, timeout, job.JobID); err != nil {
+		return diag.Errorf("error waiting for volume attaching: %s", err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", instanceID, volumeID))
+	return resourceComputeVolumeAttachRead(ctx, d, meta)
+}
+
+func resourceComputeVolumeAttachRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	ecsClient, err := cfg.ComputeV1Client(region)
+	if err != nil {
+		return diag.Errorf("error creating compute client: %s", err)
+	}
+
+	parts := strings.SplitN(d.Id(), "/", 2)
+	if len(parts) != 2 {
+		return diag.Errorf("invalid ID format, want '<instance_id>/<volume_id>', but got '%s'", d.Id())
+	}
+	attachment, err := block_devices.Get(ecsClient, parts[0], parts[1]).Extract()
+	if err != nil {
+		return diag.Errorf("error retrieving volume attachment: %s", err)
+	}
+
+	d.Set("region", region)
+	d.Set("instance_id", attachment.ServerId)
+	d.Set("volume_id", attachment.VolumeId)
+	d.Set("device", attachment.Device)
+	return nil
+}
+
+func resourceComputeVolumeAttachDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	ecsClient, err := cfg.ComputeV1Client(region)
+	if err != nil {
+		return diag.Errorf("error creating compute client: %s", err)
+	}
+	evsClient, err := cfg.BlockStorageV1Client(region)
+	if err != nil {
+		return diag.Errorf("error creating EVS client: %s", err)
+	}
+
+	instanceID := d.Get("instance_id").(string)
+	volumeID := d.Get("volume_id").(string)
+	job, err := block_devices.Detach(ecsClient, instanceID, volumeID).ExtractJobResponse()
+	if err != nil {
+		return diag.Errorf("error detaching volume %s from server %s: %s", volumeID, instanceID, err)
+	}
+
+	// 卸载任务由云硬盘的任务接口查询, 与云服务器的任务接口路径相同
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"INIT", "RUNNING"},
+		Target:       []string{"SUCCESS"},
+		Refresh:      volumeJobRefreshFunc(evsClient, job.JobID),
+		Timeout:      d.Timeout(schema.TimeoutDelete),
+		Delay:        5 * time.Second,
+		PollInterval: 5 * time.Second,
+	}
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return diag.Errorf("error waiting for volume detaching: %s", err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func volumeJobRefreshFunc(client *golangsdk.ServiceClient, jobID string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		job, err := jobs.GetJobDetails(client, jobID).ExtractJob()
+		if err != nil {
+			return nil, "ERROR", err
+		}
+		return job, job.Status, nil
+	}
+}
@@ -0,0 +1,9 @@
+package evs
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// 语法错误的资源文件, 用于测试扫描出错时继续处理其他文件
+func ResourceEvsVolume() *schema.Resource {
+	return &schema.Resource{
@@ -0,0 +1,23 @@
+// ---------------------------------------------------------------
+// *** AUTO GENERATED CODE ***
+// @Product RDS
+// ---------------------------------------------------------------
+
+package rds
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func ResourceBackup() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: resourceBackupRead,
+	}
+}
+
+func resourceBackupRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
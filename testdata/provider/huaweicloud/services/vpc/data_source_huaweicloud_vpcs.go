@@ -0,0 +1,68 @@
+package vpc
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/invoker/retry"
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/vpc/v3/model"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+)
+
+func DataSourceVpcs() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVpcsRead,
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceVpcsRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	vpcV3, err := cfg.HcVpcV3Client(cfg.GetRegion(d))
+	if err != nil {
+		return diag.Errorf("error creating VPC v3 client: %s", err)
+	}
+
+	ids := []string{}
+	request := &model.ListVpcsRequest{}
+	for {
+		// 查询失败时重试
+		resp, err := vpcV3.ListVpcsInvoker(request).
+			WithRetry(3, func(i interface{}) bool {
+				return false
+			}, retry.NewFixedBackoff(1000)).
+			Invoke()
+		if err != nil {
+			return diag.Errorf("error retrieving VPCs: %s", err)
+		}
+
+		if resp.Vpcs == nil || len(*resp.Vpcs) == 0 {
+			break
+		}
+		for _, vpc := range *resp.Vpcs {
+			ids = append(ids, vpc.Id)
+		}
+		if resp.PageInfo == nil || resp.PageInfo.NextMarker == nil {
+			break
+		}
+		request.Marker = resp.PageInfo.NextMarker
+	}
+	d.SetId(cfg.GetRegion(d))
+	d.Set("ids", ids)
+	return nil
+}
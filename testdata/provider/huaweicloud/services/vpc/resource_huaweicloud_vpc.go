@@ -0,0 +1,174 @@
+package vpc
+
+import (
+	"context"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	v2model "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/vpc/v2/model"
+	v3 "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/vpc/v3"
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/vpc/v3/model"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+)
+
+func ResourceVirtualPrivateCloudV1() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceVirtualPrivateCloudCreate,
+		ReadContext:   resourceVirtualPrivateCloudRead,
+		UpdateContext: resourceVirtualPrivateCloudUpdate,
+		DeleteContext: resourceVirtualPrivateCloudDelete,
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"cidr": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tags": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceVirtualPrivateCloudCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	client, err := cfg.HcVpcV3Client(cfg.GetRegion(d))
+	if err != nil {
+		return diag.Errorf("error creating VPC v3 client: %s", err)
+	}
+
+	name := d.Get("name").(string)
+	cidr := d.Get("cidr").(string)
+	createReq := model.CreateVpcRequest{
+		Body: &model.CreateVpcRequestBody{
+			Vpc: &model.CreateVpcOption{
+				Name: &name,
+				Cidr: &cidr,
+			},
+		},
+	}
+	resp, err := client.CreateVpc(&createReq)
+	if err != nil {
+		return diag.Errorf("error creating VPC: %s", err)
+	}
+
+	d.SetId(resp.Vpc.Id)
+	return resourceVirtualPrivateCloudRead(ctx, d, meta)
+}
+
+func resourceVirtualPrivateCloudRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	client, err := cfg.HcVpcV3Client(cfg.GetRegion(d))
+	if err != nil {
+		return diag.Errorf("error creating VPC v3 client: %s", err)
+	}
+
+	resp, err := client.ShowVpc(buildShowVpcRequest(d.Id()))
+	if err != nil {
+		return diag.Errorf("error retrieving VPC: %s", err)
+	}
+
+	d.Set("name", resp.Vpc.Name)
+	d.Set("cidr", resp.Vpc.Cidr)
+	d.Set("description", resp.Vpc.Description)
+
+	// 标签只能通过 v2 接口查询
+	vpcV2, err := cfg.HcVpcV2Client(cfg.GetRegion(d))
+	if err != nil {
+		return diag.Errorf("error creating VPC v2 client: %s", err)
+	}
+
+	tagsResp, err := vpcV2.ShowVpcTags(&v2model.ShowVpcTagsRequest{VpcId: d.Id()})
+	if err != nil {
+		return diag.Errorf("error retrieving VPC tags: %s", err)
+	}
+
+	tags := make(map[string]string)
+	if tagsResp.Tags != nil {
+		for _, tag := range *tagsResp.Tags {
+			tags[tag.Key] = tag.Value
+		}
+	}
+	d.Set("tags", tags)
+	return nil
+}
+
+func buildShowVpcRequest(id string) *model.ShowVpcRequest {
+	return &model.ShowVpcRequest{VpcId: id}
+}
+
+func resourceVirtualPrivateCloudUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	client, err := cfg.HcVpcV3Client(cfg.GetRegion(d))
+	if err != nil {
+		return diag.Errorf("error creating VPC v3 client: %s", err)
+	}
+
+	name := d.Get("name").(string)
+	description := d.Get("description").(string)
+	_, err = client.UpdateVpc(&model.UpdateVpcRequest{
+		VpcId: d.Id(),
+		Body: &model.UpdateVpcRequestBody{
+			Vpc: &model.UpdateVpcOption{
+				Name:        &name,
+				Description: &description,
+			},
+		},
+	})
+	if err != nil {
+		return diag.Errorf("error updating VPC: %s", err)
+	}
+	return resourceVirtualPrivateCloudRead(ctx, d, meta)
+}
+
+func resourceVirtualPrivateCloudDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	vpcClient, err := cfg.HcVpcV3Client(cfg.GetRegion(d))
+	if err != nil {
+		return diag.Errorf("error creating VPC v3 client: %s", err)
+	}
+
+	if err := deleteVpc(vpcClient, d.Id()); err != nil {
+		return diag.Errorf("error deleting VPC: %s", err)
+	}
+
+	// 通过 v1 接口确认VPC已经删除
+	v1Client, err := cfg.NetworkingV1Client(cfg.GetRegion(d))
+	if err != nil {
+		return diag.Errorf("error creating VPC v1 client: %s", err)
+	}
+	getPath := v1Client.ServiceURL("vpcs", d.Id())
+	getOpt := golangsdk.RequestOpts{
+		KeepResponseBody: true,
+		OkCodes:          []int{404},
+	}
+	if _, err := v1Client.Request("GET", getPath, &getOpt); err != nil {
+		return diag.Errorf("error waiting for VPC (%s) to be deleted: %s", d.Id(), err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func deleteVpc(vpcClient *v3.VpcClient, id string) error {
+	_, err := vpcClient.DeleteVpc(&model.DeleteVpcRequest{VpcId: id})
+	return err
+}
@@ -0,0 +1,122 @@
+package vpc
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	bandwidthsv1 "github.com/chnsz/golangsdk/openstack/networking/v1/bandwidths"
+	"github.com/chnsz/golangsdk/openstack/networking/v2/bandwidths"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+)
+
+func ResourceVpcBandWidthV2() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceVpcBandWidthV2Create,
+		ReadContext:   resourceVpcBandWidthV2Read,
+		UpdateContext: resourceVpcBandWidthV2Update,
+		DeleteContext: resourceVpcBandWidthV2Delete,
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"size": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"charge_mode": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+	}
+}
+
+func resourceVpcBandWidthV2Create(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	networkingClient, err := cfg.NetworkingV2Client(region)
+	if err != nil {
+		return diag.Errorf("error creating networking v2 client: %s", err)
+	}
+
+	createOpts := bandwidths.CreateOpts{
+		Name:       d.Get("name").(string),
+		Size:       d.Get("size").(int),
+		ChargeMode: bandwidths.ChargeMode(d.Get("charge_mode").(string)),
+	}
+	b, err := bandwidths.Create(networkingClient, createOpts).Extract()
+	if err != nil {
+		return diag.Errorf("error creating bandwidth: %s", err)
+	}
+	d.SetId(b.ID)
+
+	// 创建完成后通过 v2.0 接口确认带宽可用
+	if _, err := bandwidths.Get(networkingClient, d.Id()).Extract(); err != nil {
+		return diag.Errorf("error waiting for bandwidth (%s) to become active: %s", d.Id(), err)
+	}
+	return resourceVpcBandWidthV2Read(ctx, d, meta)
+}
+
+func resourceVpcBandWidthV2Read(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	bwClient, err := cfg.NetworkingV1Client(region)
+	if err != nil {
+		return diag.Errorf("error creating networking v1 client: %s", err)
+	}
+
+	b, err := bandwidthsv1.Get(bwClient, d.Id()).Extract()
+	if err != nil {
+		return diag.Errorf("error retrieving bandwidth: %s", err)
+	}
+
+	d.Set("region", region)
+	d.Set("name", b.Name)
+	d.Set("size", b.Size)
+	return nil
+}
+
+func resourceVpcBandWidthV2Update(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	bwClient, err := cfg.NetworkingV1Client(region)
+	if err != nil {
+		return diag.Errorf("error creating networking v1 client: %s", err)
+	}
+
+	updateOpts := bandwidthsv1.UpdateOpts{
+		Name: d.Get("name").(string),
+		Size: d.Get("size").(int),
+	}
+	_, err = bandwidthsv1.Update(bwClient, d.Id(), updateOpts).Extract()
+	if err != nil {
+		return diag.Errorf("error updating bandwidth: %s", err)
+	}
+	return resourceVpcBandWidthV2Read(ctx, d, meta)
+}
+
+func resourceVpcBandWidthV2Delete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	networkingClient, err := cfg.NetworkingV2Client(region)
+	if err != nil {
+		return diag.Errorf("error creating networking v2 client: %s", err)
+	}
+
+	if err := bandwidths.Delete(networkingClient, d.Id()).ExtractErr(); err != nil {
+		return diag.Errorf("error deleting bandwidth: %s", err)
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,123 @@
+package vpc
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/chnsz/golangsdk/openstack/networking/v1/subnets"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+)
+
+func ResourceVpcSubnetV1() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceVpcSubnetCreate,
+		ReadContext:   resourceVpcSubnetRead,
+		UpdateContext: resourceVpcSubnetUpdate,
+		DeleteContext: resourceVpcSubnetDelete,
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"cidr": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"gateway_ip": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"vpc_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+	}
+}
+
+func resourceVpcSubnetCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	subnetClient, err := cfg.NetworkingV1Client(region)
+	if err != nil {
+		return diag.Errorf("error creating VPC client: %s", err)
+	}
+
+	createOpts := subnets.CreateOpts{
+		Name:      d.Get("name").(string),
+		CIDR:      d.Get("cidr").(string),
+		GatewayIP: d.Get("gateway_ip").(string),
+		VpcID:     d.Get("vpc_id").(string),
+	}
+	n, err := subnets.Create(subnetClient, createOpts).Extract()
+	if err != nil {
+		return diag.Errorf("error creating VPC subnet: %s", err)
+	}
+
+	d.SetId(n.ID)
+	return resourceVpcSubnetRead(ctx, d, meta)
+}
+
+func resourceVpcSubnetRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	subnetClient, err := cfg.NetworkingV1Client(region)
+	if err != nil {
+		return diag.Errorf("error creating VPC client: %s", err)
+	}
+
+	n, err := subnets.Get(subnetClient, d.Id()).Extract()
+	if err != nil {
+		return diag.Errorf("error retrieving VPC subnet: %s", err)
+	}
+
+	d.Set("region", region)
+	d.Set("name", n.Name)
+	d.Set("cidr", n.CIDR)
+	return nil
+}
+
+func resourceVpcSubnetUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	subnetClient, err := cfg.NetworkingV1Client(region)
+	if err != nil {
+		return diag.Errorf("error creating VPC client: %s", err)
+	}
+
+	updateOpts := subnets.UpdateOpts{
+		Name: d.Get("name").(string),
+	}
+	vpcID := d.Get("vpc_id").(string)
+	_, err = subnets.Update(subnetClient, vpcID, d.Id(), updateOpts).Extract()
+	if err != nil {
+		return diag.Errorf("error updating VPC subnet: %s", err)
+	}
+	return resourceVpcSubnetRead(ctx, d, meta)
+}
+
+func resourceVpcSubnetDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	subnetClient, err := cfg.NetworkingV1Client(region)
+	if err != nil {
+		return diag.Errorf("error creating VPC client: %s", err)
+	}
+
+	vpcID := d.Get("vpc_id").(string)
+	if err := subnets.Delete(subnetClient, vpcID, d.Id()).ExtractErr(); err != nil {
+		return diag.Errorf("error deleting VPC subnet: %s", err)
+	}
+
+	d.SetId("")
+	return nil
+}
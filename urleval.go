@@ -0,0 +1,998 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// baseURLMarker 表示 ServiceClient 的 endpoint, 例如 client.ResourceBaseURL()
+// 计算结束后只保留最后一个标记之后的部分, 前面的 endpoint 由 catalog 生成
+const baseURLMarker = "\x00"
+
+// maxEvalDepth 辅助函数调用的最大深度, 防止递归调用
+const maxEvalDepth = 16
+
+// 保存已经加载的SDK包, key: 包的目录
+var urlEvaluators = make(map[string]*urlEvaluator)
+
+// specialURLFuncs 需要特殊处理的URL函数, key: 包的目录后缀 + "." + 函数名
+// 同一个API的两个URL函数分别使用 region:floatingip_id 和 id, 统一为一个路径
+var specialURLFuncs = map[string]string{
+	"/dns/v2/ptrrecords/.baseURL":     "reverse/floatingips/{region}:{floatingip_id}",
+	"/dns/v2/ptrrecords/.resourceURL": "reverse/floatingips/{region}:{floatingip_id}",
+}
+
+// urlEvaluator 对SDK包中构造URL的函数做常量折叠, 返回带有占位符的路径模板
+// 支持的表达式: 跨文件的常量和变量, 字符串拼接, fmt.Sprintf, path.Join, strings.Join,
+// client.ServiceURL/ResourceBaseURL, 以及本包或者其他SDK包中的辅助函数
+// 函数的参数和无法计算的字段使用占位符表示, 例如 {id}, {project_id}
+type urlEvaluator struct {
+	dir        string
+	fset       *token.FileSet
+	fileNames  []string                   // 排序后的文件名
+	files      map[string]*ast.File       // key: 文件名
+	sources    map[string][]byte          // key: 文件名
+	values     map[string]ast.Expr        // 包级别的常量和变量
+	valueFiles map[string]*ast.File       // 常量和变量所在的文件
+	funcs      map[string]*ast.FuncDecl   // 包级别的函数, 不包括方法
+	funcFiles  map[string]*ast.File       // 函数所在的文件
+	types      map[string]*ast.TypeSpec   // 包级别的类型
+	methods    map[string][]*ast.FuncDecl // key: 接收者的类型名称, value: 方法
+}
+
+// evalScope 计算一个函数时的上下文
+type evalScope struct {
+	file    *ast.File
+	vars    map[string]string
+	lists   map[string][]string // 可变参数, eg: parts ...string
+	unknown map[string]bool     // 已经赋值但是无法计算的局部变量
+	alts    map[string][]string // 在条件分支中赋了不同值的局部变量的所有可能值
+	depth   int
+	// 调用本包的URL函数时使用参数名作为占位符, 用于计算请求函数中的URL, 与单独计算URL函数的结果保持一致
+	paramPlaceholders bool
+}
+
+func newEvalScope(file *ast.File, depth int) *evalScope {
+	return &evalScope{
+		file:    file,
+		vars:    make(map[string]string),
+		lists:   make(map[string][]string),
+		unknown: make(map[string]bool),
+		alts:    make(map[string][]string),
+		depth:   depth,
+	}
+}
+
+// clone 复制上下文, 条件分支中的赋值不影响分支外
+func (s *evalScope) clone() *evalScope {
+	c := newEvalScope(s.file, s.depth)
+	c.paramPlaceholders = s.paramPlaceholders
+	for k, v := range s.vars {
+		c.vars[k] = v
+	}
+	for k, v := range s.lists {
+		c.lists[k] = v
+	}
+	for k := range s.unknown {
+		c.unknown[k] = true
+	}
+	for k, v := range s.alts {
+		c.alts[k] = v
+	}
+	return c
+}
+
+// setValues 设置局部变量所有可能的值, values 为空表示无法计算
+func (s *evalScope) setValues(name string, values []string) {
+	delete(s.vars, name)
+	delete(s.alts, name)
+	delete(s.unknown, name)
+	switch len(values) {
+	case 0:
+		s.unknown[name] = true
+	case 1:
+		s.vars[name] = values[0]
+	default:
+		s.alts[name] = values
+	}
+}
+
+// merge 合并各个分支结束时的上下文, 分支中赋了不同值的变量保留所有可能的值
+// 只在部分分支中无法计算的变量使用其他分支的值
+func (s *evalScope) merge(ends []*evalScope) {
+	names := make(map[string]bool)
+	for _, end := range ends {
+		for k := range end.vars {
+			names[k] = true
+		}
+		for k := range end.alts {
+			names[k] = true
+		}
+		for k := range end.unknown {
+			names[k] = true
+		}
+	}
+
+	for name := range names {
+		values := []string{}
+		unknown := false
+		for _, end := range ends {
+			candidates := end.alts[name]
+			if v, ok := end.vars[name]; ok {
+				candidates = []string{v}
+			}
+			for _, v := range candidates {
+				if !sliceContains(values, v) {
+					values = append(values, v)
+				}
+			}
+			unknown = unknown || end.unknown[name]
+		}
+
+		if len(values) > 0 || unknown {
+			s.setValues(name, values)
+		}
+	}
+}
+
+// getURLEvaluator 先从缓存中获取, 没有则加载SDK包
+func getURLEvaluator(dir string) (*urlEvaluator, error) {
+	dir = strings.TrimSuffix(dir, "/") + "/"
+	if e, ok := urlEvaluators[dir]; ok {
+		return e, nil
+	}
+
+	e, err := loadURLEvaluator(dir)
+	if err != nil {
+		return nil, err
+	}
+	urlEvaluators[dir] = e
+	return e, nil
+}
+
+func loadURLEvaluator(dir string) (*urlEvaluator, error) {
+	set := token.NewFileSet()
+	notTest := func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}
+	packs, err := parser.ParseDir(set, dir, notTest, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &urlEvaluator{
+		dir:        dir,
+		fset:       set,
+		files:      make(map[string]*ast.File),
+		sources:    make(map[string][]byte),
+		values:     make(map[string]ast.Expr),
+		valueFiles: make(map[string]*ast.File),
+		funcs:      make(map[string]*ast.FuncDecl),
+		funcFiles:  make(map[string]*ast.File),
+		types:      make(map[string]*ast.TypeSpec),
+		methods:    make(map[string][]*ast.FuncDecl),
+	}
+	for _, pack := range packs {
+		for filePath, f := range pack.Files {
+			src, err := os.ReadFile(filePath)
+			if err != nil {
+				return nil, err
+			}
+			fileName := filepath.Base(filePath)
+			e.fileNames = append(e.fileNames, fileName)
+			e.files[fileName] = f
+			e.sources[fileName] = src
+
+			for _, d := range f.Decls {
+				switch decl := d.(type) {
+				case *ast.GenDecl:
+					if decl.Tok == token.TYPE {
+						for _, spec := range decl.Specs {
+							typeSpec := spec.(*ast.TypeSpec)
+							e.types[typeSpec.Name.Name] = typeSpec
+						}
+						continue
+					}
+					if decl.Tok != token.CONST && decl.Tok != token.VAR {
+						continue
+					}
+					for _, spec := range decl.Specs {
+						valueSpec := spec.(*ast.ValueSpec)
+						for i, name := range valueSpec.Names {
+							if i < len(valueSpec.Values) {
+								e.values[name.Name] = valueSpec.Values[i]
+								e.valueFiles[name.Name] = f
+							}
+						}
+					}
+				case *ast.FuncDecl:
+					if decl.Recv == nil {
+						e.funcs[decl.Name.Name] = decl
+						e.funcFiles[decl.Name.Name] = f
+					} else if len(decl.Recv.List) > 0 {
+						recvType := localTypeName(decl.Recv.List[0].Type)
+						e.methods[recvType] = append(e.methods[recvType], decl)
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(e.fileNames)
+	return e, nil
+}
+
+// allFuncs 按文件名和位置顺序返回包中的所有函数, 不包括方法
+func (e *urlEvaluator) allFuncs() []*ast.FuncDecl {
+	funcs := []*ast.FuncDecl{}
+	for _, fileName := range e.fileNames {
+		for _, d := range e.files[fileName].Decls {
+			if fn, ok := d.(*ast.FuncDecl); ok && fn.Recv == nil {
+				funcs = append(funcs, fn)
+			}
+		}
+	}
+	return funcs
+}
+
+// funcSource 返回函数的源码
+func (e *urlEvaluator) funcSource(fn *ast.FuncDecl) string {
+	start := e.fset.Position(fn.Pos())
+	end := e.fset.Position(fn.End())
+	src := e.sources[filepath.Base(start.Filename)]
+	return string(src[start.Offset:end.Offset])
+}
+
+// evalURLFunc 计算函数所有可能返回的URL, 参数使用参数名作为占位符
+// 条件分支中的 return 按照源码顺序排在前面, eg: common/tags 的 getURL 根据 hasProjectID(c) 返回两种URL
+func (e *urlEvaluator) evalURLFunc(funcName string) ([]string, error) {
+	fn, ok := e.funcs[funcName]
+	if !ok {
+		return nil, fmt.Errorf("function %s is not found in %s", funcName, e.dir)
+	}
+
+	candidates, err := e.evalFuncCandidates(fn, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	uris := []string{}
+	for _, v := range candidates {
+		if uri := normalizeURLTemplate(v); !sliceContains(uris, uri) {
+			uris = append(uris, uri)
+		}
+	}
+	return uris, nil
+}
+
+// clientRequestMethods golangsdk ServiceClient 发起请求的方法
+var clientRequestMethods = []string{
+	"Head", "Get", "Post", "Put", "Patch", "Delete", "DeleteWithBody", "DeleteWithResponse", "DeleteWithBodyResp",
+}
+
+// requestCall SDK函数中 client 发起的一个请求
+type requestCall struct {
+	clientMethod string   // client 的方法名称, eg: Post, DeleteWithBody, 分页查询为 NewPager
+	urls         []string // 请求所有可能的URL
+	offset       int      // 方法名称前的 . 在函数源码中的位置
+}
+
+// requestCalls 按照源码顺序找到函数中 client 的请求和 pagination.NewPager, 包括闭包中的请求
+// URL参数使用函数中的局部变量计算, 支持URL函数、URL变量和直接构造的URL
+// 变量在 if/else 等分支中赋了不同的值时, 请求包含所有可能的URL
+// eg: client.Post(createURL(c), ...), client.Put(url, ...), jobClient.Get(jobClient.ServiceURL("jobs", jobID), ...)
+func (e *urlEvaluator) requestCalls(fn *ast.FuncDecl) []requestCall {
+	calls := []requestCall{}
+	if fn.Body == nil {
+		return calls
+	}
+
+	scope := e.funcScope(fn, nil, 0)
+	scope.paramPlaceholders = true
+	var visit func(n ast.Node, scope *evalScope)
+	visit = func(n ast.Node, scope *evalScope) {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			// 闭包中的赋值不影响闭包外
+			e.walkBlock(node.Body.List, scope.clone(), visit)
+		case *ast.CallExpr:
+			sel, ok := node.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return
+			}
+
+			// URL参数的位置, eg: pagination.NewPager(c, url, ...)
+			urlIndex := 0
+			if sel.Sel.Name == "NewPager" {
+				urlIndex = 1
+			} else if _, isPkg := scope.importPath(sel.X); isPkg || !sliceContains(clientRequestMethods, sel.Sel.Name) {
+				return
+			}
+			if len(node.Args) <= urlIndex {
+				return
+			}
+
+			urls, err := e.evalRequestURL(node.Args[urlIndex], scope)
+			if err != nil {
+				logDebug("can not evaluate the URL of the request", "sdk_func", fn.Name.Name,
+					"method", sel.Sel.Name, "sdk_package", e.dir, "reason", err)
+				return
+			}
+			calls = append(calls, requestCall{
+				clientMethod: sel.Sel.Name,
+				urls:         urls,
+				offset:       int(sel.Sel.Pos()-fn.Pos()) - 1,
+			})
+		}
+	}
+	e.walkBlock(fn.Body.List, scope, visit)
+	return calls
+}
+
+// evalRequestURL 计算请求的URL参数, 结果必须基于 client 的 endpoint
+// 参数直接调用本包的URL函数时, 返回函数所有可能的URL
+func (e *urlEvaluator) evalRequestURL(expr ast.Expr, scope *evalScope) ([]string, error) {
+	candidates := []string{}
+	if call, ok := expr.(*ast.CallExpr); ok {
+		if ident, ok := call.Fun.(*ast.Ident); ok {
+			if fn, ok := e.funcs[ident.Name]; ok {
+				values, err := e.evalFuncCandidates(fn, nil, scope.depth+1)
+				if err != nil {
+					return nil, err
+				}
+				candidates = values
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		values, err := e.evalAll(expr, scope)
+		if err != nil {
+			return nil, err
+		}
+		candidates = values
+	}
+
+	uris := []string{}
+	for _, v := range candidates {
+		if !strings.Contains(v, baseURLMarker) {
+			return nil, fmt.Errorf("%q is not based on the endpoint of the client", v)
+		}
+		if uri := normalizeURLTemplate(v); !sliceContains(uris, uri) {
+			uris = append(uris, uri)
+		}
+	}
+	return uris, nil
+}
+
+// normalizeURLTemplate 去除 endpoint 部分和多余的 /
+func normalizeURLTemplate(uri string) string {
+	if index := strings.LastIndex(uri, baseURLMarker); index >= 0 {
+		uri = uri[index+len(baseURLMarker):]
+	}
+	for strings.Contains(uri, "//") {
+		uri = strings.ReplaceAll(uri, "//", "/")
+	}
+	return strings.TrimPrefix(uri, "/")
+}
+
+// evalFunc 使用参数计算函数的返回值, args 为空时使用参数名作为占位符
+// 辅助函数有多个可能的返回值时使用源码中的第一个
+func (e *urlEvaluator) evalFunc(fn *ast.FuncDecl, args []string, depth int) (string, error) {
+	candidates, err := e.evalFuncCandidates(fn, args, depth)
+	if err != nil {
+		return "", err
+	}
+	return candidates[0], nil
+}
+
+// evalFuncCandidates 使用参数计算函数所有可能的返回值
+func (e *urlEvaluator) evalFuncCandidates(fn *ast.FuncDecl, args []string, depth int) ([]string, error) {
+	if depth > maxEvalDepth {
+		return nil, fmt.Errorf("the call of %s is too deep", fn.Name.Name)
+	}
+	if fn.Body == nil {
+		return nil, fmt.Errorf("function %s has no body", fn.Name.Name)
+	}
+	for key, uri := range specialURLFuncs {
+		if strings.HasSuffix(e.dir+"."+fn.Name.Name, key) {
+			return []string{baseURLMarker + uri}, nil
+		}
+	}
+
+	return e.evalBody(fn.Body, e.funcScope(fn, args, depth))
+}
+
+// funcScope 使用参数创建函数的上下文, args 为空时使用参数名作为占位符
+func (e *urlEvaluator) funcScope(fn *ast.FuncDecl, args []string, depth int) *evalScope {
+	scope := newEvalScope(e.funcFiles[fn.Name.Name], depth)
+	index := 0
+	for _, field := range fn.Type.Params.List {
+		_, variadic := field.Type.(*ast.Ellipsis)
+		for _, name := range field.Names {
+			switch {
+			case variadic && args != nil:
+				if index < len(args) {
+					scope.lists[name.Name] = args[index:]
+				} else {
+					scope.lists[name.Name] = []string{}
+				}
+			case variadic:
+				scope.lists[name.Name] = []string{fmt.Sprintf("{%s}", name.Name)}
+			case index < len(args):
+				scope.vars[name.Name] = args[index]
+			default:
+				scope.vars[name.Name] = fmt.Sprintf("{%s}", name.Name)
+			}
+			index++
+		}
+	}
+	return scope
+}
+
+// evalBody 依次处理赋值语句, 返回函数体中所有可能的返回值
+// 条件分支(if、switch 等)中的 return 都作为候选结果, 按照源码顺序排列, 不包括函数字面量中的 return
+func (e *urlEvaluator) evalBody(body *ast.BlockStmt, scope *evalScope) ([]string, error) {
+	candidates := []string{}
+	var err error
+	e.walkBlock(body.List, scope, func(n ast.Node, scope *evalScope) {
+		ret, ok := n.(*ast.ReturnStmt)
+		if !ok {
+			return
+		}
+		if len(ret.Results) == 0 {
+			err = fmt.Errorf("empty return")
+			return
+		}
+		values, evalErr := e.evalAll(ret.Results[0], scope)
+		if evalErr != nil {
+			err = evalErr
+			return
+		}
+		for _, v := range values {
+			if !sliceContains(candidates, v) {
+				candidates = append(candidates, v)
+			}
+		}
+	})
+
+	if len(candidates) > 0 {
+		return candidates, nil
+	}
+	if err == nil {
+		err = fmt.Errorf("no return statement")
+	}
+	return nil, err
+}
+
+// walkBlock 按照源码顺序处理语句中的赋值, 并使用当时的上下文访问调用表达式、函数字面量和 return 语句
+// 条件分支和循环体在复制的上下文中处理, 结束后合并到分支外的上下文, 不进入函数字面量, 由 visit 决定是否处理
+// 返回语句列表正常结束时的上下文, 在 return 处结束时返回 nil
+func (e *urlEvaluator) walkBlock(stmts []ast.Stmt, scope *evalScope, visit func(ast.Node, *evalScope)) []*evalScope {
+	for _, stmt := range stmts {
+		if ends := e.walkStmt(stmt, scope, visit); len(ends) == 0 {
+			return nil
+		} else if len(ends) > 1 || ends[0] != scope {
+			scope.merge(ends)
+		}
+	}
+	return []*evalScope{scope}
+}
+
+// walkStmt 处理一个语句, 返回语句结束时所有可能的上下文
+func (e *urlEvaluator) walkStmt(stmt ast.Stmt, scope *evalScope, visit func(ast.Node, *evalScope)) []*evalScope {
+	switch s := stmt.(type) {
+	case *ast.ReturnStmt:
+		e.visitExprs(s, scope, visit)
+		visit(s, scope)
+		return nil
+	case *ast.AssignStmt:
+		e.visitExprs(s, scope, visit)
+		e.evalAssign(s, scope)
+	case *ast.DeclStmt:
+		e.visitExprs(s, scope, visit)
+		e.evalDecl(s, scope)
+	case *ast.LabeledStmt:
+		return e.walkStmt(s.Stmt, scope, visit)
+	case *ast.BlockStmt:
+		return e.walkBlock(s.List, scope.clone(), visit)
+	case *ast.IfStmt:
+		inner := scope.clone()
+		e.walkInit(s.Init, inner, visit)
+		e.visitExprs(s.Cond, inner, visit)
+		ends := e.walkBlock(s.Body.List, inner.clone(), visit)
+		if s.Else == nil {
+			return append(ends, inner)
+		}
+		return append(ends, e.walkStmt(s.Else, inner.clone(), visit)...)
+	case *ast.SwitchStmt:
+		inner := scope.clone()
+		e.walkInit(s.Init, inner, visit)
+		e.visitExprs(s.Tag, inner, visit)
+		return e.walkCases(s.Body, inner, visit)
+	case *ast.TypeSwitchStmt:
+		inner := scope.clone()
+		e.walkInit(s.Init, inner, visit)
+		e.visitExprs(s.Assign, inner, visit)
+		return e.walkCases(s.Body, inner, visit)
+	case *ast.ForStmt:
+		// 循环体可能不执行, 也可能在 return 后结束, 总是保留循环前的上下文
+		inner := scope.clone()
+		e.walkInit(s.Init, inner, visit)
+		e.visitExprs(s.Cond, inner, visit)
+		ends := e.walkBlock(s.Body.List, inner.clone(), visit)
+		return append(ends, inner)
+	case *ast.RangeStmt:
+		inner := scope.clone()
+		e.visitExprs(s.X, inner, visit)
+		ends := e.walkBlock(s.Body.List, inner.clone(), visit)
+		return append(ends, inner)
+	default:
+		e.visitExprs(stmt, scope, visit)
+	}
+	return []*evalScope{scope}
+}
+
+// walkInit 处理 if、switch 和 for 的初始化语句, eg: if err := ...; err != nil
+func (e *urlEvaluator) walkInit(init ast.Stmt, scope *evalScope, visit func(ast.Node, *evalScope)) {
+	if init != nil {
+		e.walkStmt(init, scope, visit)
+	}
+}
+
+// walkCases 在复制的上下文中处理 switch 的每个分支, 没有 default 分支时保留 switch 前的上下文
+func (e *urlEvaluator) walkCases(body *ast.BlockStmt, scope *evalScope, visit func(ast.Node, *evalScope)) []*evalScope {
+	ends := []*evalScope{}
+	hasDefault := false
+	for _, stmt := range body.List {
+		clause, ok := stmt.(*ast.CaseClause)
+		if !ok {
+			continue
+		}
+		hasDefault = hasDefault || clause.List == nil
+		for _, expr := range clause.List {
+			e.visitExprs(expr, scope, visit)
+		}
+		ends = append(ends, e.walkBlock(clause.Body, scope.clone(), visit)...)
+	}
+	if !hasDefault {
+		ends = append(ends, scope)
+	}
+	return ends
+}
+
+// visitExprs 按照源码顺序访问节点中的调用表达式和函数字面量, 不进入函数字面量
+func (e *urlEvaluator) visitExprs(node ast.Node, scope *evalScope, visit func(ast.Node, *evalScope)) {
+	if node == nil {
+		return
+	}
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.CallExpr:
+			visit(n, scope)
+		case *ast.FuncLit:
+			visit(n, scope)
+			return false
+		}
+		return true
+	})
+}
+
+func (e *urlEvaluator) evalDecl(s *ast.DeclStmt, scope *evalScope) {
+	decl, ok := s.Decl.(*ast.GenDecl)
+	if !ok {
+		return
+	}
+	for _, spec := range decl.Specs {
+		if valueSpec, ok := spec.(*ast.ValueSpec); ok {
+			for i, name := range valueSpec.Names {
+				if i < len(valueSpec.Values) {
+					e.assign(name.Name, valueSpec.Values[i], token.DEFINE, scope)
+				}
+			}
+		}
+	}
+}
+
+func (e *urlEvaluator) evalAssign(s *ast.AssignStmt, scope *evalScope) {
+	for i, lhs := range s.Lhs {
+		// 字段赋值不影响局部变量, eg: u.Host = clusterID + "." + u.Host
+		ident, ok := lhs.(*ast.Ident)
+		if !ok || ident.Name == "_" {
+			continue
+		}
+
+		switch {
+		case len(s.Lhs) == len(s.Rhs):
+			e.assign(ident.Name, s.Rhs[i], s.Tok, scope)
+		case i == 0 && len(s.Rhs) == 1:
+			// 多返回值的调用只计算第一个返回值, eg: u, _ := url.Parse(client.ResourceBaseURL())
+			e.assign(ident.Name, s.Rhs[0], s.Tok, scope)
+		default:
+			scope.setValues(ident.Name, nil)
+		}
+	}
+}
+
+// assign 计算局部变量的值, 无法计算时标记为未知, 引用它时返回错误
+func (e *urlEvaluator) assign(name string, expr ast.Expr, tok token.Token, scope *evalScope) {
+	if tok == token.ADD_ASSIGN && scope.unknown[name] {
+		return
+	}
+	if tok == token.ADD_ASSIGN {
+		expr = &ast.BinaryExpr{X: ast.NewIdent(name), Op: token.ADD, Y: expr}
+	}
+
+	values, err := e.evalAll(expr, scope)
+	if err != nil {
+		logDebug("can not evaluate the assignment", "name", name, "sdk_package", e.dir, "reason", err)
+		// 追加的query参数等无法计算时保留原来的路径, eg: url += query.String()
+		if tok != token.ADD_ASSIGN {
+			scope.setValues(name, nil)
+		}
+		return
+	}
+	scope.setValues(name, values)
+}
+
+// maxEvalAlternatives 计算一个表达式时最多展开的可能值的组合数量
+const maxEvalAlternatives = 16
+
+// evalAll 计算表达式所有可能的值, 依次使用在条件分支中赋了不同值的局部变量的每个值
+// 只要有一个组合可以计算就忽略其他组合的错误
+func (e *urlEvaluator) evalAll(expr ast.Expr, scope *evalScope) ([]string, error) {
+	scopes := []*evalScope{scope}
+	ast.Inspect(expr, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok || len(scope.alts[ident.Name]) == 0 || len(scopes)*len(scope.alts[ident.Name]) > maxEvalAlternatives {
+			return true
+		}
+		if _, expanded := scopes[0].vars[ident.Name]; expanded {
+			return true
+		}
+
+		next := []*evalScope{}
+		for _, s := range scopes {
+			for _, v := range scope.alts[ident.Name] {
+				c := s.clone()
+				c.setValues(ident.Name, []string{v})
+				next = append(next, c)
+			}
+		}
+		scopes = next
+		return true
+	})
+
+	values := []string{}
+	var err error
+	for _, s := range scopes {
+		v, evalErr := e.eval(expr, s)
+		if evalErr != nil {
+			err = evalErr
+			continue
+		}
+		if !sliceContains(values, v) {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return nil, err
+	}
+	return values, nil
+}
+
+func (e *urlEvaluator) eval(expr ast.Expr, scope *evalScope) (string, error) {
+	switch v := expr.(type) {
+	case *ast.BasicLit:
+		if v.Kind == token.STRING {
+			return strconv.Unquote(v.Value)
+		}
+		return v.Value, nil
+	case *ast.ParenExpr:
+		return e.eval(v.X, scope)
+	case *ast.SliceExpr:
+		// eg: client.ResourceBaseURL()[8:], 只用于处理 endpoint, 忽略下标
+		return e.eval(v.X, scope)
+	case *ast.BinaryExpr:
+		if v.Op != token.ADD {
+			return "", fmt.Errorf("unsupported operator %s", v.Op)
+		}
+		left, err := e.eval(v.X, scope)
+		if err != nil {
+			return "", err
+		}
+		right, err := e.eval(v.Y, scope)
+		if err != nil {
+			return "", err
+		}
+		return left + right, nil
+	case *ast.Ident:
+		return e.evalIdent(v, scope)
+	case *ast.SelectorExpr:
+		return e.evalSelector(v, scope)
+	case *ast.CallExpr:
+		return e.evalCall(v, scope)
+	}
+	return "", fmt.Errorf("unsupported expression %T", expr)
+}
+
+func (e *urlEvaluator) evalIdent(ident *ast.Ident, scope *evalScope) (string, error) {
+	// eg: return nil, err
+	if ident.Name == "nil" {
+		return "", fmt.Errorf("nil can not be evaluated")
+	}
+	if v, ok := scope.vars[ident.Name]; ok {
+		return v, nil
+	}
+	// 有多个可能的值时使用源码中的第一个
+	if alts := scope.alts[ident.Name]; len(alts) > 0 {
+		return alts[0], nil
+	}
+	if scope.unknown[ident.Name] {
+		return "", fmt.Errorf("the local variable %s can not be evaluated", ident.Name)
+	}
+	if list, ok := scope.lists[ident.Name]; ok {
+		return strings.Join(list, "/"), nil
+	}
+
+	if expr, ok := e.values[ident.Name]; ok {
+		if scope.depth > maxEvalDepth {
+			return "", fmt.Errorf("the reference of %s is too deep", ident.Name)
+		}
+		return e.eval(expr, newEvalScope(e.valueFiles[ident.Name], scope.depth+1))
+	}
+
+	// 没有赋值的标识符作为占位符
+	return fmt.Sprintf("{%s}", ident.Name), nil
+}
+
+func (e *urlEvaluator) evalSelector(sel *ast.SelectorExpr, scope *evalScope) (string, error) {
+	switch sel.Sel.Name {
+	case "ProjectID":
+		return "{project_id}", nil
+	case "ResourceBase", "Endpoint":
+		return baseURLMarker, nil
+	}
+
+	// 其他SDK包中的常量, eg: common.RootPath
+	if pkgPath, ok := scope.importPath(sel.X); ok {
+		other, err := getURLEvaluator(basePath + "vendor/" + pkgPath)
+		if err != nil {
+			return "", err
+		}
+		expr, ok := other.values[sel.Sel.Name]
+		if !ok {
+			return "", fmt.Errorf("%s is not found in %s", sel.Sel.Name, pkgPath)
+		}
+		return other.eval(expr, newEvalScope(other.valueFiles[sel.Sel.Name], scope.depth+1))
+	}
+
+	// 结构体的字段, eg: opts.InstanceID
+	return fmt.Sprintf("{%s}", sel.Sel.Name), nil
+}
+
+func (e *urlEvaluator) evalCall(call *ast.CallExpr, scope *evalScope) (string, error) {
+	if isQueryCall(call, scope) {
+		return "", nil
+	}
+
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		fn, ok := e.funcs[fun.Name]
+		if !ok {
+			return "", fmt.Errorf("function %s is not found", fun.Name)
+		}
+		if scope.paramPlaceholders {
+			return e.evalFunc(fn, nil, scope.depth+1)
+		}
+		args, err := e.evalArgs(call, scope)
+		if err != nil {
+			return "", err
+		}
+		return e.evalFunc(fn, args, scope.depth+1)
+
+	case *ast.SelectorExpr:
+		name := fun.Sel.Name
+		pkgPath, isPkg := scope.importPath(fun.X)
+		if !isPkg {
+			// ServiceClient 的方法
+			switch name {
+			case "ResourceBaseURL":
+				return baseURLMarker, nil
+			case "ServiceURL":
+				args, err := e.evalArgs(call, scope)
+				if err != nil {
+					return "", err
+				}
+				return baseURLMarker + strings.Join(args, "/"), nil
+			case "String":
+				// url.Parse 返回的 *url.URL, eg: u.String(), 修改 Host 后仍然作为 endpoint 处理
+				return e.eval(fun.X, scope)
+			}
+			return "", fmt.Errorf("unsupported method %s", name)
+		}
+
+		switch pkgPath + "." + name {
+		case "fmt.Sprintf":
+			args, err := e.evalArgs(call, scope)
+			if err != nil {
+				return "", err
+			}
+			if len(args) == 0 {
+				return "", fmt.Errorf("fmt.Sprintf without format")
+			}
+			return formatURLTemplate(args[0], args[1:]), nil
+		case "path.Join":
+			args, err := e.evalArgs(call, scope)
+			if err != nil {
+				return "", err
+			}
+			return path.Join(args...), nil
+		case "strings.Join":
+			if len(call.Args) != 2 {
+				return "", fmt.Errorf("invalid arguments of strings.Join")
+			}
+			parts, err := e.evalList(call.Args[0], scope)
+			if err != nil {
+				return "", err
+			}
+			sep, err := e.eval(call.Args[1], scope)
+			if err != nil {
+				return "", err
+			}
+			return strings.Join(parts, sep), nil
+		case "net/url.Parse":
+			if len(call.Args) != 1 {
+				return "", fmt.Errorf("invalid arguments of url.Parse")
+			}
+			return e.eval(call.Args[0], scope)
+		case "strconv.Itoa", "strconv.FormatInt", "strconv.FormatBool":
+			if len(call.Args) == 0 {
+				return "", fmt.Errorf("invalid arguments of %s", name)
+			}
+			return e.eval(call.Args[0], scope)
+		}
+
+		// 其他SDK包中的辅助函数
+		other, err := getURLEvaluator(basePath + "vendor/" + pkgPath)
+		if err != nil {
+			return "", err
+		}
+		fn, ok := other.funcs[name]
+		if !ok {
+			return "", fmt.Errorf("function %s is not found in %s", name, pkgPath)
+		}
+		args, err := e.evalArgs(call, scope)
+		if err != nil {
+			return "", err
+		}
+		return other.evalFunc(fn, args, scope.depth+1)
+	}
+
+	return "", fmt.Errorf("unsupported call %T", call.Fun)
+}
+
+// queryMethodRegexp 构造query参数的方法, eg: opts.ToServerListQuery(), params.Encode()
+var queryMethodRegexp = regexp.MustCompile(`^(To\w*Query(String)?|Encode)$`)
+
+// isQueryCall 判断是否为构造query参数的调用, query参数不属于路径, 计算结果为空字符串
+// eg: q, err := golangsdk.BuildQueryString(&opts); url := rootURL(c) + q.String()
+func isQueryCall(call *ast.CallExpr, scope *evalScope) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	if pkgPath, isPkg := scope.importPath(sel.X); isPkg {
+		return pkgPath == "github.com/chnsz/golangsdk" && sel.Sel.Name == "BuildQueryString"
+	}
+	return queryMethodRegexp.MatchString(sel.Sel.Name)
+}
+
+// evalArgs 计算调用的参数, 展开 parts... 形式的可变参数
+func (e *urlEvaluator) evalArgs(call *ast.CallExpr, scope *evalScope) ([]string, error) {
+	args := []string{}
+	for i, arg := range call.Args {
+		if i == len(call.Args)-1 && call.Ellipsis.IsValid() {
+			list, err := e.evalList(arg, scope)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, list...)
+			continue
+		}
+
+		v, err := e.eval(arg, scope)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+	return args, nil
+}
+
+// evalList 计算字符串切片, eg: parts 或者 []string{a, b}
+func (e *urlEvaluator) evalList(expr ast.Expr, scope *evalScope) ([]string, error) {
+	switch v := expr.(type) {
+	case *ast.Ident:
+		if list, ok := scope.lists[v.Name]; ok {
+			return list, nil
+		}
+	case *ast.CompositeLit:
+		list := []string{}
+		for _, elt := range v.Elts {
+			s, err := e.eval(elt, scope)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, s)
+		}
+		return list, nil
+	}
+
+	s, err := e.eval(expr, scope)
+	if err != nil {
+		return nil, err
+	}
+	return []string{s}, nil
+}
+
+// importPath 判断表达式是否为导入的包名, 并返回包的路径
+func (s *evalScope) importPath(expr ast.Expr) (string, bool) {
+	ident, ok := expr.(*ast.Ident)
+	if !ok || s.file == nil {
+		return "", false
+	}
+	// 局部变量和参数优先
+	if _, ok := s.vars[ident.Name]; ok {
+		return "", false
+	}
+
+	for _, imp := range s.file.Imports {
+		importPath := strings.Trim(imp.Path.Value, `"`)
+		name := importPath[strings.LastIndex(importPath, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		if name == ident.Name {
+			return importPath, true
+		}
+	}
+	return "", false
+}
+
+// formatURLTemplate 按顺序使用参数替换格式化字符串中的 %s, %d, %v 等
+func formatURLTemplate(format string, args []string) string {
+	var b strings.Builder
+	argIndex := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 >= len(format) {
+			b.WriteByte(format[i])
+			continue
+		}
+
+		// 跳过标志和宽度, eg: %02d
+		j := i + 1
+		for j < len(format) && strings.IndexByte("+-# 0123456789.", format[j]) >= 0 {
+			j++
+		}
+		if j >= len(format) {
+			b.WriteString(format[i:])
+			break
+		}
+		if format[j] == '%' {
+			b.WriteByte('%')
+		} else if argIndex < len(args) {
+			b.WriteString(args[argIndex])
+			argIndex++
+		}
+		i = j
+	}
+	return b.String()
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatURLTemplate(t *testing.T) {
+	cases := []struct {
+		format string
+		args   []string
+		want   string
+	}{
+		{"vpcs/%s/subnets/%s", []string{"{vpc_id}", "{id}"}, "vpcs/{vpc_id}/subnets/{id}"},
+		{"jobs/%d", []string{"{job_id}"}, "jobs/{job_id}"},
+		{"rate/100%%/%v", []string{"{id}"}, "rate/100%/{id}"},
+		{"%02d/%s", []string{"{a}", "{b}"}, "{a}/{b}"},
+	}
+
+	for _, c := range cases {
+		if got := formatURLTemplate(c.format, c.args); got != c.want {
+			t.Errorf("formatURLTemplate(%q) = %q, want %q", c.format, got, c.want)
+		}
+	}
+}
+
+func TestEvalURLFunc(t *testing.T) {
+	defer setupFixtureScan(t)()
+
+	sdkDir := basePath + "vendor/github.com/chnsz/golangsdk/openstack/"
+	cases := []struct {
+		pkg      string
+		funcName string
+		want     []string
+	}{
+		// 常量定义在其他文件中
+		{"ecs/v1/cloudservers", "getURL", []string{"cloudservers/{serverID}"}},
+		// fmt.Sprintf
+		{"networking/v1/subnets", "updateURL", []string{"{project_id}/vpcs/{vpcid}/subnets/{id}"}},
+		// 辅助函数和 ResourceBaseURL
+		{"cce/v3/nodes", "resourceURL", []string{"clusters/{clusterid}/nodes/{nodeid}"}},
+		// 条件分支中的 return 排在函数体的 return 之前
+		{"common/tags", "getURL", []string{"{resourceType}/{id}/tags", "{project_id}/{resourceType}/{id}/tags"}},
+		// 特殊处理的URL函数使用统一的路径
+		{"dns/v2/ptrrecords", "baseURL", []string{"reverse/floatingips/{region}:{floatingip_id}"}},
+		{"dns/v2/ptrrecords", "resourceURL", []string{"reverse/floatingips/{region}:{floatingip_id}"}},
+	}
+
+	for _, c := range cases {
+		evaluator, err := getURLEvaluator(sdkDir + c.pkg)
+		if err != nil {
+			t.Fatalf("failed to load %s: %s", c.pkg, err)
+		}
+		got, err := evaluator.evalURLFunc(c.funcName)
+		if err != nil {
+			t.Errorf("failed to evaluate %s.%s: %s", c.pkg, c.funcName, err)
+			continue
+		}
+		if fmt.Sprint(got) != fmt.Sprint(c.want) {
+			t.Errorf("%s.%s = %q, want %q", c.pkg, c.funcName, got, c.want)
+		}
+	}
+}
+
+func TestEvalUnknownLocal(t *testing.T) {
+	dir := t.TempDir()
+	src := `package servers
+
+import "github.com/chnsz/golangsdk"
+
+func resourceURL(c *golangsdk.ServiceClient, opts GetOpts) string {
+	id, _ := opts.ResourceID()
+	return c.ServiceURL("servers", id)
+}
+
+func listURL(c *golangsdk.ServiceClient, opts ListOpts) string {
+	u := c.ServiceURL("servers")
+	u += opts.Filter()
+	return u
+}
+
+func queryURL(c *golangsdk.ServiceClient, opts ListOpts) (string, error) {
+	q, err := golangsdk.BuildQueryString(&opts)
+	if err != nil {
+		return nil, err
+	}
+	return c.ServiceURL("servers") + q.String(), nil
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "urls.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	evaluator, err := loadURLEvaluator(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 赋值但是无法计算的局部变量不作为占位符
+	if got, err := evaluator.evalURLFunc("resourceURL"); err == nil {
+		t.Errorf("expect an error of the unknown local variable, got %v", got)
+	}
+	// 追加的参数无法计算时保留原来的路径
+	if got, err := evaluator.evalURLFunc("listURL"); err != nil || fmt.Sprint(got) != "[servers]" {
+		t.Errorf("listURL = %v, %v, want [servers]", got, err)
+	}
+	// query参数不作为路径, 返回 nil 的分支不作为候选结果
+	if got, err := evaluator.evalURLFunc("queryURL"); err != nil || fmt.Sprint(got) != "[servers]" {
+		t.Errorf("queryURL = %v, %v, want [servers]", got, err)
+	}
+}
+
+func TestGetUriFromRequestFile(t *testing.T) {
+	defer setupFixtureScan(t)()
+
+	sdkDir := basePath + "vendor/github.com/chnsz/golangsdk/openstack/ecs/v1/cloudservers/"
+	cases := map[string][]string{
+		// 直接请求
+		"Create": {"post cloudservers"},
+		// 间接请求
+		"ForceDelete": {"post cloudservers/delete"},
+		// 多个请求, 其中一个经过两层调用
+		"ResizeAndConfirm": {"post cloudservers/{serverID}/resize", "post cloudservers/{serverID}/action"},
+		// 闭包中直接通过 ServiceURL 构造URL的请求
+		"WaitForJobSuccess": {"get jobs/{jobID}"},
+		// query参数不参与计算, 保留原来的路径
+		"List":       {"get cloudservers/detail"},
+		"ListDetail": {"get cloudservers/detail"},
+		// 在 if/else 中赋值的URL变量
+		"ListAll": {"get cloudservers/detail", "get cloudservers"},
+	}
+
+	for funcName, want := range cases {
+		got := []string{}
+		for _, uri := range getUriFromRequestFile(sdkDir, funcName, true) {
+			got = append(got, uri.httpMethod+" "+uri.url)
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("getUriFromRequestFile(%s) = %v, want %v", funcName, got, want)
+		}
+	}
+}
+
+func TestIsSdkRequestFunc(t *testing.T) {
+	defer setupFixtureScan(t)()
+
+	cases := []struct {
+		sdkPath string
+		name    string
+		want    bool
+	}{
+		{"github.com/chnsz/golangsdk/openstack/networking/v2/bandwidths", "Create", true},
+		// 类型转换
+		{"github.com/chnsz/golangsdk/openstack/networking/v2/bandwidths", "ChargeMode", false},
+	}
+	for _, c := range cases {
+		if got := isSdkRequestFunc(c.sdkPath, c.name); got != c.want {
+			t.Errorf("isSdkRequestFunc(%s, %s) = %v, want %v", c.sdkPath, c.name, got, c.want)
+		}
+	}
+}
+
+func TestStatusCodes(t *testing.T) {
+	defer setupFixtureScan(t)()
+
+	sdkDir := basePath + "vendor/github.com/chnsz/golangsdk/openstack/"
+	cases := []struct {
+		pkg      string
+		funcName string
+		want     []string
+	}{
+		// 每个请求使用各自的 OkCodes
+		{"ecs/v1/cloudservers", "ResizeAndConfirm", []string{"cloudservers/{serverID}/resize:[200]", "cloudservers/{serverID}/action:[202]"}},
+		// 通过变量定义的 RequestOpts
+		{"cce/v3/nodes", "Create", []string{"clusters/{clusterid}/nodes:[201]"}},
+		// 分页查询使用GET请求默认的状态码
+		{"ecs/v1/flavors", "List", []string{"cloudservers/flavors:[200]"}},
+		// 默认的状态码来自 vendor 中的 golangsdk
+		{"networking/v2/bandwidths", "Delete", []string{"{project_id}/bandwidths/{id}:[200 202 204]"}},
+	}
+
+	for _, c := range cases {
+		got := []string{}
+		for _, uri := range getUriFromRequestFile(sdkDir+c.pkg+"/", c.funcName, true) {
+			got = append(got, fmt.Sprintf("%s:%v", uri.url, uri.statusCodes))
+		}
+		if strings.Join(got, ",") != strings.Join(c.want, ",") {
+			t.Errorf("status codes of %s.%s = %v, want %v", c.pkg, c.funcName, got, c.want)
+		}
+	}
+
+	// 无法解析 golangsdk 时使用内置的状态码
+	if got := parseDefaultOkCodes(basePath + "provider_client.go"); fmt.Sprint(got) != fmt.Sprint(builtinOkCodes) {
+		t.Errorf("unexpected default OK codes: %v", got)
+	}
+}
+
+func TestPagination(t *testing.T) {
+	defer setupFixtureScan(t)()
+
+	sdkDir := basePath + "vendor/github.com/chnsz/golangsdk/openstack/"
+	cases := []struct {
+		pkg      string
+		funcName string
+		want     string
+	}{
+		// 根据 XxxPage 嵌入的基础类型确定分页方式
+		{"networking/v1/subnets", "List", paginationMarker},
+		{"ecs/v1/flavors", "List", paginationLink},
+		// 非分页查询
+		{"cce/v3/nodes", "Create", ""},
+	}
+
+	for _, c := range cases {
+		for _, uri := range getUriFromRequestFile(sdkDir+c.pkg+"/", c.funcName, true) {
+			if uri.pagination != c.want {
+				t.Errorf("pagination of %s.%s = %q, want %q", c.pkg, c.funcName, uri.pagination, c.want)
+			}
+		}
+	}
+
+	params := []apiParameter{{name: "limit", in: "query"}, {name: "page_no", in: "query"}}
+	if got := parametersPagination(params); got != paginationPage {
+		t.Errorf("parametersPagination(%v) = %q, want %q", params, got, paginationPage)
+	}
+}
+
+func TestActionRequests(t *testing.T) {
+	defer setupFixtureScan(t)()
+
+	sdkDir := basePath + "vendor/github.com/chnsz/golangsdk/openstack/"
+	cases := []struct {
+		pkg      string
+		funcName string
+		want     []string
+	}{
+		// 调用者通过只有一个键的map构造请求体
+		{"ecs/v1/cloudservers", "Stop", []string{"cloudservers/{serverID}/action#os-stop"}},
+		{"ecs/v1/cloudservers", "ResizeAndConfirm", []string{"cloudservers/{serverID}/resize", "cloudservers/{serverID}/action#confirmResize"}},
+		// 请求体由参数传入, 无法确定操作
+		{"ecs/v1/cloudservers", "serverAction", []string{"cloudservers/{serverID}/action"}},
+		// 请求体由辅助函数构造, 键使用辅助函数的参数拼接
+		{"compute/v2/extensions/secgroups", "AddServer", []string{"servers/{id}/action#addSecurityGroup"}},
+		{"compute/v2/extensions/secgroups", "RemoveServer", []string{"servers/{id}/action#removeSecurityGroup"}},
+	}
+
+	for _, c := range cases {
+		got := []string{}
+		for _, uri := range getUriFromRequestFile(sdkDir+c.pkg+"/", c.funcName, true) {
+			got = append(got, pathKey(uri.url, uri.action))
+		}
+		if strings.Join(got, ",") != strings.Join(c.want, ",") {
+			t.Errorf("actions of %s.%s = %v, want %v", c.pkg, c.funcName, got, c.want)
+		}
+	}
+}
+
+func TestQualifiedOperationId(t *testing.T) {
+	defer setupFixtureScan(t)()
+
+	cases := []struct {
+		uris []CloudUri
+		want []string
+	}{
+		{parseUriFromSdk(golangsdkPrefix+"ecs/v1/cloudservers", "Get"), []string{"ecs.v1.cloudservers.Get"}},
+		// 一个函数发起多个请求时使用 action 或者路径的最后一段区分
+		{parseUriFromSdk(golangsdkPrefix+"ecs/v1/cloudservers", "ResizeAndConfirm"),
+			[]string{"ecs.v1.cloudservers.ResizeAndConfirm.resize", "ecs.v1.cloudservers.ResizeAndConfirm.confirmResize"}},
+		{parseUriFromSdk2(hcsdkPrefix+"vpc/v3", "ListVpcsInvoker"), []string{"vpc.v3.ListVpcs"}},
+	}
+
+	for _, c := range cases {
+		got := []string{}
+		for _, uri := range c.uris {
+			got = append(got, uri.operationId)
+		}
+		if strings.Join(got, ",") != strings.Join(c.want, ",") {
+			t.Errorf("operationIds = %v, want %v", got, c.want)
+		}
+	}
+}
@@ -3,7 +3,6 @@ package main
 import (
 	"go/ast"
 	"go/token"
-	"os"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -16,8 +15,25 @@ type CloudUri struct {
 	httpMethod     string
 	resourceType   string
 	operationId    string
+	summary        string // SDK函数注释的第一句
 	filePath       string
 	serviceCatalog config.ServiceCatalog
+	consumes       string         // 请求的 Content-Type
+	parameters     []apiParameter // path, query, header 参数
+	requestBody    *jsonSchema
+	responseBody   *jsonSchema
+	statusCodes    []int    // 成功的HTTP状态码
+	action         string   // 共用 .../action 路径的操作, 由请求体的根节点区分, eg: os-stop
+	category       string   // read, write, delete, action
+	sensitive      []string // 匹配的敏感API规则
+	pagination     string   // 分页方式: marker, offset, page, link, 非分页查询为空
+	polling        string   // 运行时重复发起的调用: waiter, retry
+	jobStatus      bool     // 是否是查询异步任务状态的请求
+
+	// 来源信息, 指定 -provenance 时输出
+	sdkFunction       string // 资源中调用的SDK函数或者方法
+	terraformFunction string // 发起调用的资源函数
+	source            string // 调用所在的位置 file:line
 }
 
 func sliceContains(s []string, e string) bool {
@@ -54,36 +70,168 @@ func removeDuplicateValues(array []string) []string {
 	return list
 }
 
+// withMainTag 将关键资源的tag放在第一个, 其他tags按照名称排序
+func withMainTag(mainTag string, tags []string) []string {
+	others := []string{}
+	for _, v := range tags {
+		if v != mainTag {
+			others = append(others, v)
+		}
+	}
+	sort.Strings(others)
+	return append([]string{mainTag}, others...)
+}
+
+// resourceBasePath 返回 catalog 在相对路径前加入的前缀, eg: /v1/{project_id}/
+func resourceBasePath(catalog config.ServiceCatalog) string {
+	resourceBase := "/"
+	if catalog.Version != "" {
+		resourceBase = resourceBase + catalog.Version + "/"
+	}
+
+	if !catalog.WithOutProjectID {
+		resourceBase = resourceBase + "{project_id}/"
+	}
+
+	if catalog.ResourceBase != "" {
+		resourceBase = resourceBase + catalog.ResourceBase + "/"
+	}
+	return resourceBase
+}
+
+// fullPath 返回请求的完整路径, 不同版本的client可能构造相同的相对路径, eg: v1 和 v2.0 的 {project_id}/bandwidths/{id}
+// huaweicloud-sdk-go-v3 的请求已经是完整路径
+func fullPath(v CloudUri) string {
+	if strings.HasPrefix(v.url, "/") {
+		return v.url
+	}
+	return resourceBasePath(v.serviceCatalog) + v.url
+}
+
+// sortCloudUri 按照完整路径、action、HTTP方法和产品排序, 相同路径的请求相邻, 保证每次输出的顺序相同
+func sortCloudUri(uris []CloudUri) {
+	sort.SliceStable(uris, func(i, j int) bool {
+		a, b := uris[i], uris[j]
+		if pathA, pathB := fullPath(a), fullPath(b); pathA != pathB {
+			return pathA < pathB
+		}
+		if a.action != b.action {
+			return a.action < b.action
+		}
+		if a.httpMethod != b.httpMethod {
+			return a.httpMethod < b.httpMethod
+		}
+		if a.resourceType != b.resourceType {
+			return a.resourceType < b.resourceType
+		}
+		return a.operationId < b.operationId
+	})
+}
+
+// removeDuplicateCloudUri 按照完整路径、HTTP方法和 action 去重, 与输出的YAML中的键保持一致
+// 不同产品的 client 可能构造相同的路径, eg: ECS 和 EVS 的 jobs/{job_id}, 排序后保留产品名称较小的请求
 func removeDuplicateCloudUri(array []CloudUri) []CloudUri {
 	keys := make(map[string]CloudUri)
 	list := []string{}
 	rt := []CloudUri{}
 
+	sortCloudUri(array)
 	for _, v := range array {
-		entry := v.url + v.httpMethod + v.resourceType
-		entry = strings.ToLower(entry)
-		if _, ok := keys[entry]; !ok {
+		entry := strings.ToLower(strings.Join([]string{fullPath(v), v.httpMethod, v.action}, " "))
+		if pre, ok := keys[entry]; !ok {
 			keys[entry] = v
 			list = append(list, entry)
+		} else if pre.polling == "" && v.polling != "" {
+			// 同一个请求既直接调用又在等待、重试中调用时, 按照重复发起的调用处理
+			pre.polling = v.polling
+			keys[entry] = pre
 		}
 	}
-	sort.Strings(list)
 	for i := 0; i < len(list); i++ {
-		item := list[i]
-		rt = append(rt, keys[item])
+		rt = append(rt, keys[list[i]])
 	}
 	return rt
 }
 
-func filePathExists(path string) bool {
-	_, err := os.Stat(path) //os.Stat获取文件信息
-	if err != nil {
-		if os.IsExist(err) {
+// projectIDSegment 路径开头的 project_id, catalog 没有设置 WithOutProjectID 时由 catalog 加入
+const projectIDSegment = "{project_id}/"
+
+// dropProjectIDCandidates 去除路径中再次加入 project_id 的请求
+// URL函数的条件分支可能返回 {project_id}/xxx 和 xxx 两个路径, catalog 已经加入 project_id 时只保留后者
+// forceDrop 为 true 时去除所有路径中包含 {project_id} 的请求, 用于 replaceTagUri 强制加入 project_id 的 tags 请求
+func dropProjectIDCandidates(uris []CloudUri, forceDrop bool) []CloudUri {
+	rst := []CloudUri{}
+	for _, v := range uris {
+		if forceDrop && strings.Contains(v.url, "{project_id}") {
+			logDebug("drop the URL with project ID", "url", v.url)
+			continue
+		}
+		if !forceDrop && !v.serviceCatalog.WithOutProjectID && strings.HasPrefix(v.url, projectIDSegment) {
+			twin := strings.TrimPrefix(v.url, projectIDSegment)
+			if hasCloudUri(uris, twin, v.httpMethod, v.action) {
+				logDebug("drop the URL with project ID", "url", v.url)
+				continue
+			}
+		}
+		rst = append(rst, v)
+	}
+	return rst
+}
+
+func hasCloudUri(uris []CloudUri, url, httpMethod, action string) bool {
+	for _, v := range uris {
+		if v.url == url && v.httpMethod == httpMethod && v.action == action {
 			return true
 		}
-		return false
 	}
-	return true
+	return false
+}
+
+// operationIdScheme operationId 的格式, 输出在YAML的 info 中
+// package 为SDK包相对 openstack/ 或 services/ 的路径, 使用 . 连接, eg: ecs.v1.cloudservers.Get, vpc.v3.ShowVpc
+// 一个SDK函数发起多个请求时, 使用 action 或者路径中最后一个非占位符的部分区分, eg: ecs.v1.cloudservers.ResizeAndConfirm.resize
+const operationIdScheme = "{package}.{function}[.{action or last path segment}]"
+
+// qualifiedOperationId 返回使用SDK包限定的 operationId
+func qualifiedOperationId(sdkFilePath, funcName string) string {
+	pkg := strings.TrimPrefix(strings.TrimPrefix(sdkFilePath, golangsdkPrefix), hcsdkPrefix)
+	return strings.ReplaceAll(pkg, "/", ".") + "." + funcName
+}
+
+// qualifyOperations 一个SDK函数发起多个请求时, 在 operationId 后增加区分的后缀
+// 只是路径前是否有 {project_id} 不同的请求是同一个操作, eg: common/tags 的 getURL 在不同分支中返回的URL
+func qualifyOperations(uris []CloudUri) []CloudUri {
+	requests := []string{}
+	for _, v := range uris {
+		request := v.httpMethod + " " + v.action + " " + strings.TrimPrefix(v.url, projectIDSegment)
+		if !sliceContains(requests, request) {
+			requests = append(requests, request)
+		}
+	}
+	if len(requests) < 2 {
+		return uris
+	}
+	for i := range uris {
+		qualifier := uris[i].action
+		if qualifier == "" {
+			segments := strings.Split(uris[i].url, "/")
+			for j := len(segments) - 1; j >= 0 && qualifier == ""; j-- {
+				if segments[j] != "" && !pathPlaceholderReg.MatchString(segments[j]) {
+					qualifier = segments[j]
+				}
+			}
+		}
+		if qualifier == "" {
+			qualifier = uris[i].httpMethod
+		}
+		uris[i].operationId += "." + qualifier
+	}
+	return uris
+}
+
+// isActionUrl 判断是否是通过请求体区分操作的 .../action 路径
+func isActionUrl(url string) bool {
+	return url == "action" || strings.HasSuffix(url, "/action")
 }
 
 func mapToStandardHttpMethod(httpMethod string) string {
@@ -114,6 +262,9 @@ func getCatalogFromName(fileName string) (*config.ServiceCatalog, string) {
 	return nil, strings.ToUpper(catalog)
 }
 
+// getServiceCatalog 根据client名称获取 ServiceCatalog, 测试时可以替换
+var getServiceCatalog = config.GetServiceCatalog
+
 func parseEndPointByClient(clientName string) *config.ServiceCatalog {
-	return config.GetServiceCatalog(clientName)
+	return getServiceCatalog(clientName)
 }